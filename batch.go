@@ -0,0 +1,120 @@
+package rms
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+const (
+	// GET_ORDER_MAX_ORDERS は楽天ペイ受注APIの注文情報の取得で一度に指定できる注文番号の数です。
+	GET_ORDER_MAX_ORDERS = 100
+
+	// GET_ORDER_BATCH_DEFAULT_PARALLELISM は GetOrderBatch で同時実行数を指定しない場合の同時実行数です。
+	GET_ORDER_BATCH_DEFAULT_PARALLELISM = 4
+)
+
+// GetOrderBatchResult は GetOrderBatch で得られる結果です。
+type GetOrderBatchResult struct {
+	// OrderModelList は取得できた受注モデルリストです。RMSが返却した順によらず、指定された注文番号の順に並びます。同じ注文番号が複数指定された場合は1件のみ含まれます。
+	OrderModelList []GetOrderOrderModel
+
+	// Errors は取得できなかった注文のエラーです。キーは注文番号です。
+	Errors map[string]*OrderError
+}
+
+// GetOrderBatch は楽天ペイ受注APIで任意の数の注文情報を取得します。oList は注文番号、v はバージョン番号です。
+// 注文番号は100件ずつに分割され、最大 parallelism 件まで同時に取得します。parallelism が0以下の場合は GET_ORDER_BATCH_DEFAULT_PARALLELISM 件まで同時に取得します。
+// 注文ごとのエラーは結果の Errors に格納され、全体の処理は継続します。通信エラー等で取得を継続できない場合はエラーを返却します。
+func (a *RMSApi) GetOrderBatch(ctx context.Context, oList []string, v int, parallelism int) (*GetOrderBatchResult, error) {
+	if !a.initialized() {
+		return nil, ErrNotInitialized
+	}
+	if parallelism <= 0 {
+		parallelism = GET_ORDER_BATCH_DEFAULT_PARALLELISM
+	}
+	chunks := chunkStrings(oList, GET_ORDER_MAX_ORDERS)
+	results := make([]*GetOrderResponse, len(chunks))
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		orderErr = map[string]*OrderError{}
+		sem      = make(chan struct{}, parallelism)
+	)
+	for i, chunk := range chunks {
+		wg.Add(1)
+		go func(i int, chunk []string) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+			r, err := a.GetOrderContext(ctx, chunk, v)
+
+			mu.Lock()
+			defer mu.Unlock()
+			apiErr := &APIError{}
+			switch {
+			case err == nil:
+				results[i] = r
+				for _, oe := range r.OrderErrors() {
+					orderErr[oe.OrderNumber] = oe
+				}
+			case errors.As(err, &apiErr) && len(apiErr.OrderErrors) > 0:
+				for _, oe := range apiErr.OrderErrors {
+					orderErr[oe.OrderNumber] = oe
+				}
+			default:
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+			}
+		}(i, chunk)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	orders := map[string]GetOrderOrderModel{}
+	for _, r := range results {
+		if r == nil {
+			continue
+		}
+		for _, o := range r.OrderModelList {
+			orders[o.OrderNumber] = o
+		}
+	}
+	result := &GetOrderBatchResult{Errors: orderErr}
+	for _, n := range oList {
+		if o, ok := orders[n]; ok {
+			result.OrderModelList = append(result.OrderModelList, o)
+			delete(orders, n)
+		}
+	}
+	return result, nil
+}
+
+// chunkStrings は s を最大 n 件ずつに分割します。
+func chunkStrings(s []string, n int) [][]string {
+	chunks := [][]string{}
+	for len(s) > n {
+		chunks = append(chunks, s[:n])
+		s = s[n:]
+	}
+	if len(s) > 0 {
+		chunks = append(chunks, s)
+	}
+	return chunks
+}
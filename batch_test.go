@@ -0,0 +1,74 @@
+package rms
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestGetOrderBatch_分割取得(t *testing.T) {
+	var calls, running, maxRunning int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+
+		req := GetOrderRequest{}
+		json.NewDecoder(r.Body).Decode(&req)
+		res := GetOrderResponse{}
+		for _, o := range req.OrderNumberList {
+			if strings.HasPrefix(o, "NG") {
+				res.GetOrderMessageModelList = append(res.GetOrderMessageModelList, GetOrderMessageModel{CommonMessageModelResponse{"ERROR", "ORDER_EXT_API_GET_ORDER_ERROR_005", "注文番号が存在しません。"}, o})
+				continue
+			}
+			res.GetOrderMessageModelList = append(res.GetOrderMessageModelList, GetOrderMessageModel{CommonMessageModelResponse{"INFO", "ORDER_EXT_API_GET_ORDER_INFO_101", "受注情報取得に成功しました。"}, o})
+			// RMSは指定された順に返却するとは限らないため、逆順で返却する
+			res.OrderModelList = append([]GetOrderOrderModel{{OrderNumber: o}}, res.OrderModelList...)
+		}
+		json.NewEncoder(w).Encode(res)
+	}))
+	defer ts.Close()
+
+	oList := []string{}
+	for i := 0; i < 250; i++ {
+		oList = append(oList, fmt.Sprintf("%d", i))
+	}
+	oList = append(oList, "NG-1")
+
+	a := NewRMSApi("hoge", "fuga", WithBaseURL(ts.URL))
+	r, err := a.GetOrderBatch(context.Background(), oList, 4, 2)
+	if err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	if calls != 3 {
+		t.Errorf("expected: 3, actual: %d", calls)
+	}
+	if maxRunning > 2 {
+		t.Errorf("expected: <= 2, actual: %d", maxRunning)
+	}
+	if len(r.OrderModelList) != 250 {
+		t.Errorf("expected: 250, actual: %d", len(r.OrderModelList))
+		t.FailNow()
+	}
+	for i, o := range r.OrderModelList {
+		if o.OrderNumber != oList[i] {
+			t.Errorf("expected: %s at %d, actual: %s", oList[i], i, o.OrderNumber)
+			break
+		}
+	}
+	if oe, ok := r.Errors["NG-1"]; !ok || oe.MessageCode != "ORDER_EXT_API_GET_ORDER_ERROR_005" {
+		t.Errorf("expected: ORDER_EXT_API_GET_ORDER_ERROR_005, actual: %v", r.Errors)
+	}
+}
@@ -0,0 +1,543 @@
+package rms
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/url"
+	"path"
+)
+
+const (
+	// RMS WEB SERVICEのR-Cabinet APIの利用状況取得用のエンドポイントです。
+	CABINET_USAGE_GET_URL = "https://api.rms.rakuten.co.jp/es/1.0/cabinet/usage/get"
+
+	// RMS WEB SERVICEのR-Cabinet APIのフォルダ一覧取得用のエンドポイントです。
+	CABINET_FOLDERS_GET_URL = "https://api.rms.rakuten.co.jp/es/1.0/cabinet/folders/get"
+
+	// RMS WEB SERVICEのR-Cabinet APIのフォルダ内の画像一覧取得用のエンドポイントです。
+	CABINET_FOLDER_FILES_GET_URL = "https://api.rms.rakuten.co.jp/es/1.0/cabinet/folder/files/get"
+
+	// RMS WEB SERVICEのR-Cabinet APIの画像検索用のエンドポイントです。
+	CABINET_FILES_SEARCH_URL = "https://api.rms.rakuten.co.jp/es/1.0/cabinet/files/search"
+
+	// RMS WEB SERVICEのR-Cabinet APIの画像登録用のエンドポイントです。
+	CABINET_FILE_INSERT_URL = "https://api.rms.rakuten.co.jp/es/1.0/cabinet/file/insert"
+
+	// RMS WEB SERVICEのR-Cabinet APIの画像削除用のエンドポイントです。
+	CABINET_FILE_DELETE_URL = "https://api.rms.rakuten.co.jp/es/1.0/cabinet/file/delete"
+
+	// RMS WEB SERVICEのR-Cabinet APIのフォルダ登録用のエンドポイントです。
+	CABINET_FOLDER_INSERT_URL = "https://api.rms.rakuten.co.jp/es/1.0/cabinet/folder/insert"
+
+	// CABINET_MAX_LIMIT はR-Cabinet APIの一覧取得で1ページに取得できる最大の件数です。
+	CABINET_MAX_LIMIT = 100
+
+	// CABINET_RESULT_CODE_SUCCESS はR-Cabinet APIで正常に処理が行われた場合の結果コードです。
+	CABINET_RESULT_CODE_SUCCESS = "0"
+)
+
+type (
+	// CabinetResult はR-Cabinet APIの処理結果です。
+	CabinetResult struct {
+		// ResultCode は結果コードです。0 の場合は正常に処理が行われています。
+		ResultCode string `xml:"resultCode"`
+	}
+
+	// CabinetUsageModel はR-Cabinetの利用状況です。
+	CabinetUsageModel struct {
+		// MaxSpace は契約容量(MB)です。
+		MaxSpace float64 `xml:"MaxSpace"`
+
+		// FolderMax は登録できるフォルダの上限数です。
+		FolderMax int `xml:"FolderMax"`
+
+		// FileMax は1フォルダに登録できる画像の上限数です。
+		FileMax int `xml:"FileMax"`
+
+		// UseSpace は使用容量(KB)です。
+		UseSpace float64 `xml:"UseSpace"`
+
+		// AvailSpace は残り容量(KB)です。
+		AvailSpace float64 `xml:"AvailSpace"`
+
+		// UseFolderCount は登録済みのフォルダ数です。
+		UseFolderCount int `xml:"UseFolderCount"`
+
+		// AvailFolderCount は登録できる残りのフォルダ数です。
+		AvailFolderCount int `xml:"AvailFolderCount"`
+	}
+
+	// CabinetUsageGetResult はR-Cabinet APIの利用状況取得の処理結果です。
+	CabinetUsageGetResult struct {
+		CabinetResult
+		CabinetUsageModel
+	}
+
+	// CabinetUsageGetResponse はR-Cabinet APIの利用状況取得で得られるレスポンスです。
+	CabinetUsageGetResponse struct {
+		// Status は処理状況です。
+		Status ItemApiStatus `xml:"status"`
+
+		// Result は処理結果です。
+		Result CabinetUsageGetResult `xml:"cabinetUsageGetResult"`
+	}
+
+	// CabinetFolderModel はR-Cabinetのフォルダです。
+	CabinetFolderModel struct {
+		// FolderID はフォルダIDです。
+		FolderID int `xml:"FolderId"`
+
+		// FolderName はフォルダ名です。
+		FolderName string `xml:"FolderName"`
+
+		// FolderNode はフォルダの階層です。最上位のフォルダは1です。
+		FolderNode int `xml:"FolderNode"`
+
+		// FolderPath はフォルダのパスです。
+		FolderPath string `xml:"FolderPath"`
+
+		// FileCount はフォルダ内の画像の数です。
+		FileCount int `xml:"FileCount"`
+
+		// FileSize はフォルダ内の画像の合計サイズ(KB)です。
+		FileSize float64 `xml:"FileSize"`
+
+		// TimeStamp は更新日時です。
+		TimeStamp string `xml:"TimeStamp"`
+	}
+
+	// CabinetFoldersGetResult はR-Cabinet APIのフォルダ一覧取得の処理結果です。
+	CabinetFoldersGetResult struct {
+		CabinetResult
+
+		// FolderAllCount はフォルダの総数です。
+		FolderAllCount int `xml:"folderAllCount"`
+
+		// FolderCount は取得したフォルダの数です。
+		FolderCount int `xml:"folderCount"`
+
+		// Folders はフォルダです。
+		Folders []CabinetFolderModel `xml:"folders>folder"`
+	}
+
+	// CabinetFoldersGetResponse はR-Cabinet APIのフォルダ一覧取得で得られるレスポンスです。
+	CabinetFoldersGetResponse struct {
+		// Status は処理状況です。
+		Status ItemApiStatus `xml:"status"`
+
+		// Result は処理結果です。
+		Result CabinetFoldersGetResult `xml:"cabinetFoldersGetResult"`
+	}
+
+	// CabinetFileModel はR-Cabinetに登録された画像です。
+	CabinetFileModel struct {
+		// FolderID は画像が登録されたフォルダのフォルダIDです。
+		FolderID int `xml:"FolderId"`
+
+		// FolderName はフォルダ名です。
+		FolderName string `xml:"FolderName"`
+
+		// FolderNode はフォルダの階層です。
+		FolderNode int `xml:"FolderNode"`
+
+		// FolderPath はフォルダのパスです。
+		FolderPath string `xml:"FolderPath"`
+
+		// FileID は画像IDです。
+		FileID int `xml:"FileId"`
+
+		// FileName は画像名です。
+		FileName string `xml:"FileName"`
+
+		// FileURL は画像のURLです。商品画像にはこのURLを指定します。
+		FileURL string `xml:"FileUrl"`
+
+		// FilePath は画像保存名です。
+		FilePath string `xml:"FilePath"`
+
+		// FileType は画像の形式です。以下のいずれかが入力されます。
+		// 1: JPEG
+		// 2: GIF
+		// 3: アニメーションGIF
+		// 4: PNG
+		// 5: TIFF
+		// 6: BMP
+		FileType int `xml:"FileType"`
+
+		// FileSize は画像のサイズ(KB)です。
+		FileSize float64 `xml:"FileSize"`
+
+		// FileWidth は画像の幅(px)です。
+		FileWidth int `xml:"FileWidth"`
+
+		// FileHeight は画像の高さ(px)です。
+		FileHeight int `xml:"FileHeight"`
+
+		// FileAccessDate は最終アクセス日です。
+		FileAccessDate string `xml:"FileAccessDate"`
+
+		// TimeStamp は更新日時です。
+		TimeStamp string `xml:"TimeStamp"`
+	}
+
+	// CabinetFilesResult はR-Cabinet APIの画像一覧取得、画像検索の処理結果です。
+	CabinetFilesResult struct {
+		CabinetResult
+
+		// FileAllCount は条件に一致した画像の総数です。
+		FileAllCount int `xml:"fileAllCount"`
+
+		// FileCount は取得した画像の数です。
+		FileCount int `xml:"fileCount"`
+
+		// Files は画像です。
+		Files []CabinetFileModel `xml:"files>file"`
+	}
+
+	// CabinetFolderFilesGetResponse はR-Cabinet APIのフォルダ内の画像一覧取得で得られるレスポンスです。
+	CabinetFolderFilesGetResponse struct {
+		// Status は処理状況です。
+		Status ItemApiStatus `xml:"status"`
+
+		// Result は処理結果です。
+		Result CabinetFilesResult `xml:"cabinetFolderFilesGetResult"`
+	}
+
+	// CabinetFilesSearchResponse はR-Cabinet APIの画像検索で得られるレスポンスです。
+	CabinetFilesSearchResponse struct {
+		// Status は処理状況です。
+		Status ItemApiStatus `xml:"status"`
+
+		// Result は処理結果です。
+		Result CabinetFilesResult `xml:"cabinetFilesSearchResult"`
+	}
+
+	// CabinetFileSearchCondition はR-Cabinet APIの画像検索の条件です。FileID、FilePath、FileName のいずれかを指定します。
+	CabinetFileSearchCondition struct {
+		// FileID は画像IDです。
+		FileID int
+
+		// FilePath は画像保存名です。
+		FilePath string
+
+		// FileName は画像名です。
+		FileName string
+
+		// Offset は取得するページです。1から始まります。0の場合は1ページ目を取得します。
+		Offset int
+
+		// Limit は1ページあたりの件数です。1~100まで指定することができます。それ以外の場合は100件取得します。
+		Limit int
+	}
+
+	// CabinetFileInsertCondition はR-Cabinet APIの画像登録の条件です。
+	CabinetFileInsertCondition struct {
+		// FileName は画像名です。
+		FileName string `xml:"fileName"`
+
+		// FolderID は登録先のフォルダIDです。
+		FolderID int `xml:"folderId"`
+
+		// FilePath は画像保存名です。指定しない場合は自動で採番されます。
+		FilePath string `xml:"filePath,omitempty"`
+
+		// OverWrite は FilePath が同じ画像が登録済みの場合に上書きするかどうかです。
+		OverWrite bool `xml:"overWrite,omitempty"`
+	}
+
+	// CabinetFolderInsertCondition はR-Cabinet APIのフォルダ登録の条件です。
+	CabinetFolderInsertCondition struct {
+		// FolderName はフォルダ名です。
+		FolderName string `xml:"folderName"`
+
+		// DirectoryName はフォルダのURLに使用するディレクトリ名です。指定しない場合は自動で採番されます。
+		DirectoryName string `xml:"directoryName,omitempty"`
+
+		// UpperFolderID は親のフォルダIDです。最上位に登録する場合は指定しません。
+		UpperFolderID int `xml:"upperFolderId,omitempty"`
+	}
+
+	// CabinetRequest はR-Cabinet APIの画像登録・削除、フォルダ登録のリクエストです。
+	CabinetRequest struct {
+		XMLName xml.Name `xml:"request"`
+
+		// FileInsert は登録する画像です。
+		FileInsert *CabinetFileInsertCondition `xml:"fileInsertRequest>file,omitempty"`
+
+		// FileDelete は削除する画像の画像IDです。
+		FileDelete *int `xml:"fileDeleteRequest>file>fileId,omitempty"`
+
+		// FolderInsert は登録するフォルダです。
+		FolderInsert *CabinetFolderInsertCondition `xml:"folderInsertRequest>folder,omitempty"`
+	}
+
+	// CabinetFileInsertResponse はR-Cabinet APIの画像登録で得られるレスポンスです。
+	CabinetFileInsertResponse struct {
+		// Status は処理状況です。
+		Status ItemApiStatus `xml:"status"`
+
+		// Result は処理結果です。
+		Result struct {
+			CabinetResult
+
+			// FileID は採番された画像IDです。
+			FileID int `xml:"FileId"`
+		} `xml:"cabinetFileInsertResult"`
+	}
+
+	// CabinetFileDeleteResponse はR-Cabinet APIの画像削除で得られるレスポンスです。
+	CabinetFileDeleteResponse struct {
+		// Status は処理状況です。
+		Status ItemApiStatus `xml:"status"`
+
+		// Result は処理結果です。
+		Result CabinetResult `xml:"cabinetFileDeleteResult"`
+	}
+
+	// CabinetFolderInsertResponse はR-Cabinet APIのフォルダ登録で得られるレスポンスです。
+	CabinetFolderInsertResponse struct {
+		// Status は処理状況です。
+		Status ItemApiStatus `xml:"status"`
+
+		// Result は処理結果です。
+		Result struct {
+			CabinetResult
+
+			// FolderID は採番されたフォルダIDです。
+			FolderID int `xml:"FolderId"`
+		} `xml:"cabinetFolderInsertResult"`
+	}
+)
+
+// GetCabinetUsage はR-Cabinet APIで契約容量や使用容量等の利用状況を取得します。
+func (a *RMSApi) GetCabinetUsage() (*CabinetUsageModel, error) {
+	return a.GetCabinetUsageContext(context.Background())
+}
+
+// GetCabinetUsageContext は GetCabinetUsage にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) GetCabinetUsageContext(ctx context.Context) (*CabinetUsageModel, error) {
+	if !a.initialized() {
+		return nil, ErrNotInitialized
+	}
+	result := CabinetUsageGetResponse{}
+	if err := a.sendCabinetXML(ctx, "GET", CABINET_USAGE_GET_URL, nil, nil, &result, &result.Status, &result.Result.CabinetResult); err != nil {
+		return nil, err
+	}
+	return &result.Result.CabinetUsageModel, nil
+}
+
+// GetCabinetFolders はR-Cabinet APIでフォルダの一覧を取得します。offset は取得するページで1から始まり、limit は1ページあたりの件数です。
+func (a *RMSApi) GetCabinetFolders(offset, limit int) (*CabinetFoldersGetResult, error) {
+	return a.GetCabinetFoldersContext(context.Background(), offset, limit)
+}
+
+// GetCabinetFoldersContext は GetCabinetFolders にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) GetCabinetFoldersContext(ctx context.Context, offset, limit int) (*CabinetFoldersGetResult, error) {
+	if !a.initialized() {
+		return nil, ErrNotInitialized
+	}
+	result := CabinetFoldersGetResponse{}
+	if err := a.sendCabinetXML(ctx, "GET", CABINET_FOLDERS_GET_URL, cabinetPageParams(offset, limit), nil, &result, &result.Status, &result.Result.CabinetResult); err != nil {
+		return nil, err
+	}
+	return &result.Result, nil
+}
+
+// GetCabinetFolderFiles はR-Cabinet APIでフォルダ内の画像の一覧を取得します。offset は取得するページで1から始まり、limit は1ページあたりの件数です。
+func (a *RMSApi) GetCabinetFolderFiles(folderID, offset, limit int) (*CabinetFilesResult, error) {
+	return a.GetCabinetFolderFilesContext(context.Background(), folderID, offset, limit)
+}
+
+// GetCabinetFolderFilesContext は GetCabinetFolderFiles にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) GetCabinetFolderFilesContext(ctx context.Context, folderID, offset, limit int) (*CabinetFilesResult, error) {
+	if !a.initialized() {
+		return nil, ErrNotInitialized
+	}
+	params := cabinetPageParams(offset, limit)
+	params.Add("folderId", fmt.Sprintf("%d", folderID))
+	result := CabinetFolderFilesGetResponse{}
+	if err := a.sendCabinetXML(ctx, "GET", CABINET_FOLDER_FILES_GET_URL, params, nil, &result, &result.Status, &result.Result.CabinetResult); err != nil {
+		return nil, err
+	}
+	return &result.Result, nil
+}
+
+// SearchCabinetFiles はR-Cabinet APIで画像を検索します。cond は検索条件です。
+func (a *RMSApi) SearchCabinetFiles(cond *CabinetFileSearchCondition) (*CabinetFilesResult, error) {
+	return a.SearchCabinetFilesContext(context.Background(), cond)
+}
+
+// SearchCabinetFilesContext は SearchCabinetFiles にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) SearchCabinetFilesContext(ctx context.Context, cond *CabinetFileSearchCondition) (*CabinetFilesResult, error) {
+	if !a.initialized() {
+		return nil, ErrNotInitialized
+	}
+	if cond == nil || (cond.FileID == 0 && cond.FilePath == "" && cond.FileName == "") {
+		return nil, &ValidationError{Field: "fileId", Message: "画像ID、画像保存名、画像名のいずれかを指定してください。"}
+	}
+	params := cabinetPageParams(cond.Offset, cond.Limit)
+	if cond.FileID != 0 {
+		params.Add("fileId", fmt.Sprintf("%d", cond.FileID))
+	}
+	if cond.FilePath != "" {
+		params.Add("filePath", cond.FilePath)
+	}
+	if cond.FileName != "" {
+		params.Add("fileName", cond.FileName)
+	}
+	result := CabinetFilesSearchResponse{}
+	if err := a.sendCabinetXML(ctx, "GET", CABINET_FILES_SEARCH_URL, params, nil, &result, &result.Status, &result.Result.CabinetResult); err != nil {
+		return nil, err
+	}
+	return &result.Result, nil
+}
+
+// InsertCabinetFile はR-Cabinet APIで画像を登録し、採番された画像IDを返却します。file は画像の内容で、読み込みながら送信するため全体をメモリに保持しません。
+// file は一度しか読み込めないため、再試行の設定に関わらず通信に失敗した場合も再送しません。
+func (a *RMSApi) InsertCabinetFile(cond *CabinetFileInsertCondition, file io.Reader) (int, error) {
+	return a.InsertCabinetFileContext(context.Background(), cond, file)
+}
+
+// InsertCabinetFileContext は InsertCabinetFile にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) InsertCabinetFileContext(ctx context.Context, cond *CabinetFileInsertCondition, file io.Reader) (int, error) {
+	if !a.initialized() {
+		return 0, ErrNotInitialized
+	}
+	if cond.FileName == "" {
+		return 0, &ValidationError{Field: "fileName", Message: "画像名を指定してください。"}
+	}
+	req, err := xml.Marshal(CabinetRequest{FileInsert: cond})
+	if err != nil {
+		return 0, err
+	}
+
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		pw.CloseWithError(writeCabinetFile(mw, append([]byte(xml.Header), req...), cabinetUploadName(cond), file))
+	}()
+	status, byteArray, err := a.sendStream(ctx, "POST", CABINET_FILE_INSERT_URL, mw.FormDataContentType(), pr)
+	// 送信が途中で失敗した場合に書き込み側のgoroutineを終了させます。
+	pr.Close()
+	if err != nil {
+		return 0, err
+	}
+
+	result := CabinetFileInsertResponse{}
+	if err := parseCabinetResult(status, byteArray, &result, &result.Status, &result.Result.CabinetResult); err != nil {
+		return 0, err
+	}
+	return result.Result.FileID, nil
+}
+
+// DeleteCabinetFile はR-Cabinet APIで画像を削除します。fileID は画像IDです。
+func (a *RMSApi) DeleteCabinetFile(fileID int) error {
+	return a.DeleteCabinetFileContext(context.Background(), fileID)
+}
+
+// DeleteCabinetFileContext は DeleteCabinetFile にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) DeleteCabinetFileContext(ctx context.Context, fileID int) error {
+	if !a.initialized() {
+		return ErrNotInitialized
+	}
+	result := CabinetFileDeleteResponse{}
+	return a.sendCabinetXML(ctx, "POST", CABINET_FILE_DELETE_URL, nil, &CabinetRequest{FileDelete: &fileID}, &result, &result.Status, &result.Result)
+}
+
+// InsertCabinetFolder はR-Cabinet APIでフォルダを登録し、採番されたフォルダIDを返却します。
+func (a *RMSApi) InsertCabinetFolder(cond *CabinetFolderInsertCondition) (int, error) {
+	return a.InsertCabinetFolderContext(context.Background(), cond)
+}
+
+// InsertCabinetFolderContext は InsertCabinetFolder にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) InsertCabinetFolderContext(ctx context.Context, cond *CabinetFolderInsertCondition) (int, error) {
+	if !a.initialized() {
+		return 0, ErrNotInitialized
+	}
+	if cond.FolderName == "" {
+		return 0, &ValidationError{Field: "folderName", Message: "フォルダ名を指定してください。"}
+	}
+	result := CabinetFolderInsertResponse{}
+	if err := a.sendCabinetXML(ctx, "POST", CABINET_FOLDER_INSERT_URL, nil, &CabinetRequest{FolderInsert: cond}, &result, &result.Status, &result.Result.CabinetResult); err != nil {
+		return 0, err
+	}
+	return result.Result.FolderID, nil
+}
+
+// cabinetPageParams は一覧取得のページ指定をクエリパラメータに変換します。
+func cabinetPageParams(offset, limit int) url.Values {
+	if offset <= 0 {
+		offset = 1
+	}
+	if limit <= 0 || limit > CABINET_MAX_LIMIT {
+		limit = CABINET_MAX_LIMIT
+	}
+	params := url.Values{}
+	params.Add("offset", fmt.Sprintf("%d", offset))
+	params.Add("limit", fmt.Sprintf("%d", limit))
+	return params
+}
+
+// cabinetUploadName はマルチパートのファイル名として送信する名前を返却します。
+func cabinetUploadName(cond *CabinetFileInsertCondition) string {
+	if cond.FilePath != "" {
+		return path.Base(cond.FilePath)
+	}
+	return cond.FileName
+}
+
+// writeCabinetFile は画像登録のリクエストのXMLと画像の内容をマルチパートで書き込みます。
+func writeCabinetFile(mw *multipart.Writer, req []byte, name string, file io.Reader) error {
+	if err := mw.WriteField("xml", string(req)); err != nil {
+		return err
+	}
+	fw, err := mw.CreateFormFile("file", name)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(fw, file); err != nil {
+		return err
+	}
+	return mw.Close()
+}
+
+// sendCabinetXML はR-Cabinet APIにリクエストを送信し、レスポンスを result に格納します。
+// s と r は result に含まれる処理状況と処理結果で、結果コードが0以外の場合は APIError を返却します。
+func (a *RMSApi) sendCabinetXML(ctx context.Context, method, u string, params url.Values, req interface{}, result interface{}, s *ItemApiStatus, r *CabinetResult) error {
+	var body []byte
+	if req != nil {
+		b, err := xml.Marshal(req)
+		if err != nil {
+			return err
+		}
+		body = append([]byte(xml.Header), b...)
+	}
+	status, byteArray, err := a.send(ctx, method, u, "text/xml; charset=utf-8", params, body)
+	if err != nil {
+		return err
+	}
+	return parseCabinetResult(status, byteArray, result, s, r)
+}
+
+// parseCabinetResult はR-Cabinet APIのレスポンスを result に格納し、処理に失敗している場合は APIError を返却します。
+func parseCabinetResult(status int, byteArray []byte, result interface{}, s *ItemApiStatus, r *CabinetResult) error {
+	err := xml.Unmarshal(byteArray, result)
+	if err != nil && isSuccessStatus(status) {
+		return err
+	}
+	if isSuccessStatus(status) && r.ResultCode == CABINET_RESULT_CODE_SUCCESS {
+		return nil
+	}
+	list := []CommonMessageModelResponse{}
+	code := r.ResultCode
+	if code == "" {
+		code = s.SystemStatus
+	}
+	if code != "" {
+		list = append(list, CommonMessageModelResponse{MessageType: "ERROR", MessageCode: code, Message: s.Message})
+	}
+	return newAPIError(status, byteArray, list)
+}
@@ -0,0 +1,85 @@
+package rms
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGetCabinetUsage_利用状況の取得(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<result>
+  <status><interfaceId>cabinet.usage.get</interfaceId><systemStatus>OK</systemStatus><message>OK</message><requestId>1</requestId></status>
+  <cabinetUsageGetResult>
+    <resultCode>0</resultCode>
+    <MaxSpace>100</MaxSpace><FolderMax>1000</FolderMax><FileMax>2000</FileMax>
+    <UseSpace>1234.5</UseSpace><AvailSpace>101165.5</AvailSpace><UseFolderCount>3</UseFolderCount><AvailFolderCount>997</AvailFolderCount>
+  </cabinetUsageGetResult>
+</result>`))
+	}))
+	defer ts.Close()
+
+	a := NewRMSApi("hoge", "fuga", WithBaseURL(ts.URL))
+	u, err := a.GetCabinetUsage()
+	if err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	if u.UseSpace != 1234.5 || u.AvailFolderCount != 997 {
+		t.Errorf("expected: 1234.5 997, actual: %v %d", u.UseSpace, u.AvailFolderCount)
+	}
+}
+
+func TestInsertCabinetFile_マルチパートで送信(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mr, err := r.MultipartReader()
+		if err != nil {
+			t.Errorf("Happend undefined error: %v", err)
+			return
+		}
+		parts := map[string]string{}
+		for {
+			p, err := mr.NextPart()
+			if err == io.EOF {
+				break
+			}
+			b, _ := ioutil.ReadAll(p)
+			parts[p.FormName()] = string(b)
+		}
+		if !strings.Contains(parts["xml"], "<fileInsertRequest><file><fileName>商品画像</fileName><folderId>5</folderId>") {
+			t.Errorf("expected: fileInsertRequest, actual: %s", parts["xml"])
+		}
+		if parts["file"] != "image-data" {
+			t.Errorf("expected: image-data, actual: %s", parts["file"])
+		}
+		w.Write([]byte(`<result><status><systemStatus>OK</systemStatus><message>OK</message></status><cabinetFileInsertResult><resultCode>0</resultCode><FileId>42</FileId></cabinetFileInsertResult></result>`))
+	}))
+	defer ts.Close()
+
+	a := NewRMSApi("hoge", "fuga", WithBaseURL(ts.URL))
+	id, err := a.InsertCabinetFile(&CabinetFileInsertCondition{FileName: "商品画像", FolderID: 5, FilePath: "item.jpg"}, strings.NewReader("image-data"))
+	if err != nil || id != 42 {
+		t.Errorf("expected: 42, actual: %d %v", id, err)
+	}
+}
+
+func TestDeleteCabinetFile_エラー(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<result><status><systemStatus>NG</systemStatus><message>ファイルが存在しません。</message></status><cabinetFileDeleteResult><resultCode>3004</resultCode></cabinetFileDeleteResult></result>`))
+	}))
+	defer ts.Close()
+
+	a := NewRMSApi("hoge", "fuga", WithBaseURL(ts.URL))
+	err := a.DeleteCabinetFile(1)
+	if !errors.Is(err, &APIError{MessageCode: "3004"}) {
+		t.Errorf("expected: 3004, actual: %v", err)
+	}
+	if _, err := a.SearchCabinetFiles(&CabinetFileSearchCondition{}); !errors.As(err, new(*ValidationError)) {
+		t.Errorf("expected: ValidationError, actual: %v", err)
+	}
+}
@@ -0,0 +1,213 @@
+package rms
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// CalendarDayType は営業日カレンダーにおける1日の種別を表します。
+type CalendarDayType int
+
+const (
+	// CALENDAR_DAY_TYPE_BUSINESS_DAY は受注・お問い合わせ業務と発送業務を行う営業日です。
+	CALENDAR_DAY_TYPE_BUSINESS_DAY CalendarDayType = iota + 1
+
+	// CALENDAR_DAY_TYPE_HOLIDAY は休業日です。
+	CALENDAR_DAY_TYPE_HOLIDAY
+
+	// CALENDAR_DAY_TYPE_ORDER_ONLY は受注・お問い合わせ業務のみの営業日です。発送は行いません。
+	CALENDAR_DAY_TYPE_ORDER_ONLY
+
+	// CALENDAR_DAY_TYPE_SHIPPING_ONLY は発送業務のみの営業日です。
+	CALENDAR_DAY_TYPE_SHIPPING_ONLY
+)
+
+// CALENDAR_SEARCH_MAX_DAYS は営業日を探す際に先に進める最大の日数です。
+const CALENDAR_SEARCH_MAX_DAYS = 366
+
+// ErrNoBusinessDay は営業日を探す範囲に、条件を満たす日が見つからない場合のエラーです。
+var ErrNoBusinessDay = errors.New("No business day found in the calendar")
+
+// calendarWeekdays は営業日カレンダーの曜日と time.Weekday の対応です。
+var calendarWeekdays = map[string]time.Weekday{
+	"SUN": time.Sunday,
+	"MON": time.Monday,
+	"TUE": time.Tuesday,
+	"WED": time.Wednesday,
+	"THU": time.Thursday,
+	"FRI": time.Friday,
+	"SAT": time.Saturday,
+}
+
+type (
+	// BusinessCalendar は店舗の営業日カレンダーをもとに、営業日や発送日を計算するためのカレンダーです。NewBusinessCalendar で生成します。
+	// 日付の指定は定期的な休日(曜日)より優先されます。日時は日本標準時の日付として扱います。
+	// 営業日カレンダーの取得期間外の日付は、定期的な休日のみで判定します。
+	BusinessCalendar struct {
+		businessHoliday calendarRule
+		shippingHoliday calendarRule
+		shippingOnly    calendarRule
+	}
+
+	// calendarRule は曜日と日付による休日の指定です。
+	calendarRule struct {
+		weekdays map[time.Weekday]bool
+		dates    map[string]bool
+	}
+)
+
+// Weekdays は定期的な休日の曜日を time.Weekday に変換します。
+func (w CaleendarEventWeekday) Weekdays() ([]time.Weekday, error) {
+	list := []time.Weekday{}
+	for _, s := range w.Weekday {
+		d, ok := calendarWeekdays[s]
+		if !ok {
+			return nil, fmt.Errorf("invalid weekday: %s", s)
+		}
+		list = append(list, d)
+	}
+	return list, nil
+}
+
+// Dates は日付を日本標準時の0時の time.Time に変換します。
+func (d CaleendarEventEventDate) Dates() ([]time.Time, error) {
+	list := []time.Time{}
+	for _, s := range d.EventDate {
+		t, err := time.ParseInLocation(CALENDAR_EVENT_DATE_FORMAT, s, jst)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, t)
+	}
+	return list, nil
+}
+
+// parseTimestamps は StimestampYmd 等の文字列から Stimestamp 等の日時を設定します。文字列が空の場合や形式が不正な場合は nil のままです。
+// 形式が不正な文字列があった場合は、他の日時を設定したうえで最初のエラーを返却します。
+func (h *ShopHoliday) parseTimestamps() error {
+	var first error
+	parse := func(s string) *time.Time {
+		if s == "" {
+			return nil
+		}
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			if first == nil {
+				first = err
+			}
+			return nil
+		}
+		return &t
+	}
+	h.Stimestamp = parse(h.StimestampYmd)
+	h.Etimestamp = parse(h.EtimestampYmd)
+	h.StimestampMail = parse(h.StimestampMailYmd)
+	h.EtimestampMail = parse(h.EtimestampMailYmd)
+	return first
+}
+
+// NewBusinessCalendar は営業日カレンダーから BusinessCalendar を生成します。曜日や日付の形式が不正な場合はエラーを返却します。
+func NewBusinessCalendar(c *ShopCalendar) (*BusinessCalendar, error) {
+	b := &BusinessCalendar{}
+	var err error
+	if b.businessHoliday, err = newCalendarRule(c.BusinessHoliday); err != nil {
+		return nil, err
+	}
+	if b.shippingHoliday, err = newCalendarRule(c.ShippingHoliday); err != nil {
+		return nil, err
+	}
+	if b.shippingOnly, err = newCalendarRule(c.ShippingOnly); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func newCalendarRule(e CalendarEvent) (calendarRule, error) {
+	r := calendarRule{weekdays: map[time.Weekday]bool{}, dates: map[string]bool{}}
+	weekdays, err := e.RegularSchedule.Weekdays()
+	if err != nil {
+		return r, err
+	}
+	for _, w := range weekdays {
+		r.weekdays[w] = true
+	}
+	dates, err := e.EventDates.Dates()
+	if err != nil {
+		return r, err
+	}
+	for _, d := range dates {
+		r.dates[d.Format(CALENDAR_EVENT_DATE_FORMAT)] = true
+	}
+	return r, nil
+}
+
+// DayType は t の日付の種別を返却します。
+func (b *BusinessCalendar) DayType(t time.Time) CalendarDayType {
+	t = t.In(jst)
+	key := t.Format(CALENDAR_EVENT_DATE_FORMAT)
+	switch {
+	case b.businessHoliday.dates[key]:
+		return CALENDAR_DAY_TYPE_HOLIDAY
+	case b.shippingHoliday.dates[key]:
+		return CALENDAR_DAY_TYPE_ORDER_ONLY
+	case b.shippingOnly.dates[key]:
+		return CALENDAR_DAY_TYPE_SHIPPING_ONLY
+	}
+	w := t.Weekday()
+	switch {
+	case b.businessHoliday.weekdays[w]:
+		return CALENDAR_DAY_TYPE_HOLIDAY
+	case b.shippingHoliday.weekdays[w]:
+		return CALENDAR_DAY_TYPE_ORDER_ONLY
+	case b.shippingOnly.weekdays[w]:
+		return CALENDAR_DAY_TYPE_SHIPPING_ONLY
+	}
+	return CALENDAR_DAY_TYPE_BUSINESS_DAY
+}
+
+// IsBusinessDay は t の日付が休業日でないかどうかを返却します。受注・お問い合わせ業務のみ、発送業務のみの営業日も営業日として扱います。
+func (b *BusinessCalendar) IsBusinessDay(t time.Time) bool {
+	return b.DayType(t) != CALENDAR_DAY_TYPE_HOLIDAY
+}
+
+// IsShippingDay は t の日付に発送業務を行うかどうかを返却します。
+func (b *BusinessCalendar) IsShippingDay(t time.Time) bool {
+	d := b.DayType(t)
+	return d == CALENDAR_DAY_TYPE_BUSINESS_DAY || d == CALENDAR_DAY_TYPE_SHIPPING_ONLY
+}
+
+// NextShippingDay は t の日付以降で最初に発送業務を行う日を、日本標準時の0時で返却します。t の日付が発送業務を行う日の場合は t の日付を返却します。
+// 1年以内に発送業務を行う日がない場合は ErrNoBusinessDay を返却します。
+func (b *BusinessCalendar) NextShippingDay(t time.Time) (time.Time, error) {
+	d := startOfDay(t)
+	for i := 0; i < CALENDAR_SEARCH_MAX_DAYS; i++ {
+		if b.IsShippingDay(d) {
+			return d, nil
+		}
+		d = d.AddDate(0, 0, 1)
+	}
+	return time.Time{}, ErrNoBusinessDay
+}
+
+// AddBusinessDays は t の日付から n 営業日後の日付を、日本標準時の0時で返却します。n が0の場合は t の日付を返却します。
+// 営業日は IsBusinessDay で判定します。1年以内に n 営業日が見つからない場合は ErrNoBusinessDay を返却します。
+func (b *BusinessCalendar) AddBusinessDays(t time.Time, n int) (time.Time, error) {
+	d := startOfDay(t)
+	for i := 0; n > 0; i++ {
+		if i >= CALENDAR_SEARCH_MAX_DAYS {
+			return time.Time{}, ErrNoBusinessDay
+		}
+		d = d.AddDate(0, 0, 1)
+		if b.IsBusinessDay(d) {
+			n--
+		}
+	}
+	return d, nil
+}
+
+// startOfDay は t の日付の日本標準時の0時を返却します。
+func startOfDay(t time.Time) time.Time {
+	t = t.In(jst)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, jst)
+}
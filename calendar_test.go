@@ -0,0 +1,89 @@
+package rms
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func newTestBusinessCalendar(t *testing.T) *BusinessCalendar {
+	c := ShopCalendar{}
+	c.BusinessHoliday.RegularSchedule.Weekday = []string{"SUN"}
+	c.BusinessHoliday.EventDates.EventDate = []string{"20200504", "20200505"}
+	c.ShippingHoliday.RegularSchedule.Weekday = []string{"SAT"}
+	c.ShippingOnly.EventDates.EventDate = []string{"20200503"}
+	b, err := NewBusinessCalendar(&c)
+	if err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	return b
+}
+
+func TestBusinessCalendar_日の種別(t *testing.T) {
+	b := newTestBusinessCalendar(t)
+	cases := []struct {
+		date     time.Time
+		expected CalendarDayType
+	}{
+		{time.Date(2020, 5, 1, 12, 0, 0, 0, jst), CALENDAR_DAY_TYPE_BUSINESS_DAY},
+		{time.Date(2020, 5, 2, 12, 0, 0, 0, jst), CALENDAR_DAY_TYPE_ORDER_ONLY},
+		{time.Date(2020, 5, 3, 12, 0, 0, 0, jst), CALENDAR_DAY_TYPE_SHIPPING_ONLY},
+		{time.Date(2020, 5, 4, 12, 0, 0, 0, jst), CALENDAR_DAY_TYPE_HOLIDAY},
+		{time.Date(2020, 5, 10, 12, 0, 0, 0, jst), CALENDAR_DAY_TYPE_HOLIDAY},
+		// UTCでは5/5だが、日本標準時では5/6(水)の営業日
+		{time.Date(2020, 5, 5, 16, 0, 0, 0, time.UTC), CALENDAR_DAY_TYPE_BUSINESS_DAY},
+	}
+	for _, c := range cases {
+		if actual := b.DayType(c.date); actual != c.expected {
+			t.Errorf("%v expected: %v, actual: %v", c.date, c.expected, actual)
+		}
+	}
+}
+
+func TestBusinessCalendar_発送日と営業日の計算(t *testing.T) {
+	b := newTestBusinessCalendar(t)
+
+	d, err := b.NextShippingDay(time.Date(2020, 5, 2, 9, 0, 0, 0, jst))
+	if err != nil || !d.Equal(time.Date(2020, 5, 3, 0, 0, 0, 0, jst)) {
+		t.Errorf("expected: 2020-05-03, actual: %v %v", d, err)
+	}
+	d, err = b.NextShippingDay(time.Date(2020, 5, 4, 9, 0, 0, 0, jst))
+	if err != nil || !d.Equal(time.Date(2020, 5, 6, 0, 0, 0, 0, jst)) {
+		t.Errorf("expected: 2020-05-06, actual: %v %v", d, err)
+	}
+
+	d, err = b.AddBusinessDays(time.Date(2020, 5, 1, 9, 0, 0, 0, jst), 3)
+	if err != nil || !d.Equal(time.Date(2020, 5, 6, 0, 0, 0, 0, jst)) {
+		t.Errorf("expected: 2020-05-06, actual: %v %v", d, err)
+	}
+
+	c := ShopCalendar{}
+	c.BusinessHoliday.RegularSchedule.Weekday = []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}
+	closed, _ := NewBusinessCalendar(&c)
+	if _, err := closed.NextShippingDay(time.Now()); !errors.Is(err, ErrNoBusinessDay) {
+		t.Errorf("expected: ErrNoBusinessDay, actual: %v", err)
+	}
+}
+
+func TestShopHoliday_告知期間の変換(t *testing.T) {
+	h := ShopHoliday{StimestampYmd: "2020-04-20T00:00:00+09:00", EtimestampYmd: "2020-05-10T23:59:59+09:00"}
+	if err := h.parseTimestamps(); err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	if h.Stimestamp == nil || !h.Stimestamp.Equal(time.Date(2020, 4, 20, 0, 0, 0, 0, jst)) || h.StimestampMail != nil {
+		t.Errorf("expected: 2020-04-20T00:00:00+09:00, actual: %v", h.Stimestamp)
+	}
+
+	h = ShopHoliday{StimestampYmd: "2020-04-20T00:00:00+09:00", EtimestampYmd: "2020/05/10 23:59:59"}
+	if err := h.parseTimestamps(); err == nil {
+		t.Error("expected: error, actual: nil")
+	}
+	if h.Stimestamp == nil || h.Etimestamp != nil {
+		t.Errorf("expected: 2020-04-20T00:00:00+09:00 - nil, actual: %v - %v", h.Stimestamp, h.Etimestamp)
+	}
+	if _, err := (CaleendarEventWeekday{Weekday: []string{"SUNDAY"}}).Weekdays(); err == nil {
+		t.Error("expected: error, actual: nil")
+	}
+}
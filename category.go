@@ -0,0 +1,250 @@
+package rms
+
+import (
+	"context"
+	"encoding/xml"
+)
+
+const (
+	// RMS WEB SERVICEのカテゴリAPIの店舗内カテゴリ一覧取得用のエンドポイントです。
+	CATEGORIES_GET_URL = "https://api.rms.rakuten.co.jp/es/1.0/categoryapi/shop/categories/get"
+
+	// RMS WEB SERVICEのカテゴリAPIの店舗内カテゴリ登録用のエンドポイントです。
+	CATEGORY_INSERT_URL = "https://api.rms.rakuten.co.jp/es/1.0/categoryapi/shop/category/insert"
+
+	// RMS WEB SERVICEのカテゴリAPIの店舗内カテゴリ更新用のエンドポイントです。
+	CATEGORY_UPDATE_URL = "https://api.rms.rakuten.co.jp/es/1.0/categoryapi/shop/category/update"
+
+	// RMS WEB SERVICEのカテゴリAPIの店舗内カテゴリ削除用のエンドポイントです。
+	CATEGORY_DELETE_URL = "https://api.rms.rakuten.co.jp/es/1.0/categoryapi/shop/category/delete"
+
+	// RMS WEB SERVICEのカテゴリAPIの店舗内カテゴリ移動用のエンドポイントです。
+	CATEGORY_MOVE_URL = "https://api.rms.rakuten.co.jp/es/1.0/categoryapi/shop/category/move"
+
+	// CATEGORY_ROOT_ID は最上位のカテゴリの親を表すカテゴリIDです。
+	CATEGORY_ROOT_ID = 0
+)
+
+type (
+	// CategoryModel は店舗内カテゴリです。子カテゴリを再帰的に含みます。
+	CategoryModel struct {
+		// CategoryID はカテゴリIDです。
+		CategoryID int `xml:"categoryId,omitempty"`
+
+		// Name はカテゴリ名です。
+		Name string `xml:"name"`
+
+		// Status は表示状態です。以下のいずれかが入力されます。
+		// 0: 表示
+		// 1: 非表示
+		Status int `xml:"status"`
+
+		// CategoryLevel はカテゴリの階層です。最上位のカテゴリは1です。
+		CategoryLevel int `xml:"categoryLevel,omitempty"`
+
+		// ChildCategories は子カテゴリです。
+		ChildCategories []CategoryModel `xml:"childCategories>category,omitempty"`
+	}
+
+	// CategoriesGetResponse はカテゴリAPIの店舗内カテゴリ一覧取得で得られるレスポンスです。
+	CategoriesGetResponse struct {
+		// ResultCode は結果コードです。
+		ResultCode string `xml:"resultCode"`
+
+		// ResultMessageList はメッセージ一覧です。
+		ResultMessageList ResultMessageList `xml:"resultMessageList"`
+
+		// Categories は最上位のカテゴリです。
+		Categories []CategoryModel `xml:"result>categoryList>category"`
+	}
+
+	// CategoryApiResponse はカテゴリAPIの店舗内カテゴリの登録・更新・削除・移動で得られるレスポンスです。
+	CategoryApiResponse struct {
+		// ResultCode は結果コードです。
+		ResultCode string `xml:"resultCode"`
+
+		// ResultMessageList はメッセージ一覧です。
+		ResultMessageList ResultMessageList `xml:"resultMessageList"`
+
+		// CategoryID は対象のカテゴリIDです。登録の場合は採番されたカテゴリIDが入力されます。
+		CategoryID int `xml:"result>categoryId"`
+	}
+
+	// CategoryRequest はカテゴリAPIの店舗内カテゴリの登録・更新・削除・移動のリクエストです。
+	CategoryRequest struct {
+		XMLName xml.Name `xml:"request"`
+
+		// Insert は登録するカテゴリです。
+		Insert *CategoryInsertCondition `xml:"categoryInsertRequest,omitempty"`
+
+		// Update は更新するカテゴリです。
+		Update *CategoryModel `xml:"categoryUpdateRequest>category,omitempty"`
+
+		// Delete は削除するカテゴリのカテゴリIDです。
+		Delete *int `xml:"categoryDeleteRequest>categoryId,omitempty"`
+
+		// Move は移動するカテゴリです。
+		Move *CategoryMoveCondition `xml:"categoryMoveRequest,omitempty"`
+	}
+
+	// CategoryInsertCondition はカテゴリAPIの店舗内カテゴリ登録の条件です。
+	CategoryInsertCondition struct {
+		// ParentCategoryID は親のカテゴリIDです。最上位に登録する場合は CATEGORY_ROOT_ID を指定します。
+		ParentCategoryID int `xml:"parentCategoryId"`
+
+		// Category は登録するカテゴリです。子カテゴリは登録されません。
+		Category CategoryModel `xml:"category"`
+	}
+
+	// CategoryMoveCondition はカテゴリAPIの店舗内カテゴリ移動の条件です。
+	CategoryMoveCondition struct {
+		// CategoryID は移動するカテゴリIDです。
+		CategoryID int `xml:"categoryId"`
+
+		// DestParentCategoryID は移動先の親のカテゴリIDです。最上位に移動する場合は CATEGORY_ROOT_ID を指定します。
+		DestParentCategoryID int `xml:"destParentCategoryId"`
+	}
+)
+
+// Walk はカテゴリとその子孫のカテゴリを深さ優先で順に fn に渡します。fn が false を返却した場合はそれ以降のカテゴリを渡さずに false を返却します。
+func (c *CategoryModel) Walk(fn func(c *CategoryModel) bool) bool {
+	if !fn(c) {
+		return false
+	}
+	for i := range c.ChildCategories {
+		if !c.ChildCategories[i].Walk(fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// Find はカテゴリの一覧から、カテゴリIDが categoryID のカテゴリを子孫のカテゴリも含めて検索します。見つからない場合は nil を返却します。
+func (r *CategoriesGetResponse) Find(categoryID int) *CategoryModel {
+	var found *CategoryModel
+	for i := range r.Categories {
+		r.Categories[i].Walk(func(c *CategoryModel) bool {
+			if c.CategoryID == categoryID {
+				found = c
+				return false
+			}
+			return true
+		})
+		if found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// GetCategories はカテゴリAPIで店舗内カテゴリの一覧を階層構造で取得します。
+func (a *RMSApi) GetCategories() (*CategoriesGetResponse, error) {
+	return a.GetCategoriesContext(context.Background())
+}
+
+// GetCategoriesContext は GetCategories にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) GetCategoriesContext(ctx context.Context) (*CategoriesGetResponse, error) {
+	if !a.initialized() {
+		return nil, ErrNotInitialized
+	}
+	result := CategoriesGetResponse{}
+	if err := a.sendResultXML(ctx, "GET", CATEGORIES_GET_URL, nil, nil, &result, &result.ResultCode, &result.ResultMessageList); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// InsertCategory はカテゴリAPIで店舗内カテゴリを登録し、採番されたカテゴリIDを返却します。parentCategoryID は親のカテゴリIDで、最上位に登録する場合は CATEGORY_ROOT_ID を指定します。
+func (a *RMSApi) InsertCategory(parentCategoryID int, c *CategoryModel) (int, error) {
+	return a.InsertCategoryContext(context.Background(), parentCategoryID, c)
+}
+
+// InsertCategoryContext は InsertCategory にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) InsertCategoryContext(ctx context.Context, parentCategoryID int, c *CategoryModel) (int, error) {
+	if !a.initialized() {
+		return 0, ErrNotInitialized
+	}
+	cond := CategoryInsertCondition{ParentCategoryID: parentCategoryID, Category: *c}
+	cond.Category.CategoryID = 0
+	cond.Category.CategoryLevel = 0
+	cond.Category.ChildCategories = nil
+	result, err := a.sendCategory(ctx, CATEGORY_INSERT_URL, &CategoryRequest{Insert: &cond})
+	if err != nil {
+		return 0, err
+	}
+	return result.CategoryID, nil
+}
+
+// UpdateCategory はカテゴリAPIで店舗内カテゴリのカテゴリ名、表示状態を更新します。c の CategoryID で対象のカテゴリを指定します。子カテゴリは更新されません。
+func (a *RMSApi) UpdateCategory(c *CategoryModel) error {
+	return a.UpdateCategoryContext(context.Background(), c)
+}
+
+// UpdateCategoryContext は UpdateCategory にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) UpdateCategoryContext(ctx context.Context, c *CategoryModel) error {
+	if !a.initialized() {
+		return ErrNotInitialized
+	}
+	u := *c
+	u.CategoryLevel = 0
+	u.ChildCategories = nil
+	_, err := a.sendCategory(ctx, CATEGORY_UPDATE_URL, &CategoryRequest{Update: &u})
+	return err
+}
+
+// DeleteCategory はカテゴリAPIで店舗内カテゴリを削除します。
+func (a *RMSApi) DeleteCategory(categoryID int) error {
+	return a.DeleteCategoryContext(context.Background(), categoryID)
+}
+
+// DeleteCategoryContext は DeleteCategory にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) DeleteCategoryContext(ctx context.Context, categoryID int) error {
+	if !a.initialized() {
+		return ErrNotInitialized
+	}
+	_, err := a.sendCategory(ctx, CATEGORY_DELETE_URL, &CategoryRequest{Delete: &categoryID})
+	return err
+}
+
+// MoveCategory はカテゴリAPIで店舗内カテゴリを destParentCategoryID のカテゴリの下に移動します。最上位に移動する場合は CATEGORY_ROOT_ID を指定します。
+func (a *RMSApi) MoveCategory(categoryID, destParentCategoryID int) error {
+	return a.MoveCategoryContext(context.Background(), categoryID, destParentCategoryID)
+}
+
+// MoveCategoryContext は MoveCategory にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) MoveCategoryContext(ctx context.Context, categoryID, destParentCategoryID int) error {
+	if !a.initialized() {
+		return ErrNotInitialized
+	}
+	if categoryID == destParentCategoryID {
+		return &ValidationError{Field: "destParentCategoryId", Message: "移動先にカテゴリ自身を指定することはできません。"}
+	}
+	_, err := a.sendCategory(ctx, CATEGORY_MOVE_URL, &CategoryRequest{Move: &CategoryMoveCondition{categoryID, destParentCategoryID}})
+	return err
+}
+
+// SetItemCategories は商品APIで商品が所属する店舗内カテゴリを categoryIDs で置き換えます。itemURL は商品管理番号で、categoryIDs には1件以上のカテゴリIDを指定します。
+func (a *RMSApi) SetItemCategories(itemURL string, categoryIDs []int) error {
+	return a.SetItemCategoriesContext(context.Background(), itemURL, categoryIDs)
+}
+
+// SetItemCategoriesContext は SetItemCategories にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) SetItemCategoriesContext(ctx context.Context, itemURL string, categoryIDs []int) error {
+	if len(categoryIDs) == 0 {
+		return &ValidationError{Field: "categories", Message: "カテゴリIDを1件以上指定してください。"}
+	}
+	item := ItemModel{ItemURL: itemURL, Categories: []ItemCategoryModel{}}
+	for _, id := range categoryIDs {
+		item.Categories = append(item.Categories, ItemCategoryModel{id})
+	}
+	return a.UpdateItemContext(ctx, &item)
+}
+
+// sendCategory はカテゴリAPIの更新系のエンドポイントにリクエストを送信します。
+func (a *RMSApi) sendCategory(ctx context.Context, u string, req *CategoryRequest) (*CategoryApiResponse, error) {
+	result := CategoryApiResponse{}
+	if err := a.sendResultXML(ctx, "POST", u, nil, req, &result, &result.ResultCode, &result.ResultMessageList); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,83 @@
+package rms
+
+import (
+	"encoding/xml"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGetCategories_階層構造の取得(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<result>
+  <resultCode>N000</resultCode>
+  <resultMessageList><resultMessage><code>N000</code><message>Succeeded.</message></resultMessage></resultMessageList>
+  <result>
+    <categoryList>
+      <category>
+        <categoryId>1</categoryId><name>食品</name><status>0</status><categoryLevel>1</categoryLevel>
+        <childCategories>
+          <category>
+            <categoryId>2</categoryId><name>お菓子</name><status>0</status><categoryLevel>2</categoryLevel>
+            <childCategories>
+              <category><categoryId>3</categoryId><name>チョコレート</name><status>1</status><categoryLevel>3</categoryLevel></category>
+            </childCategories>
+          </category>
+        </childCategories>
+      </category>
+      <category><categoryId>4</categoryId><name>雑貨</name><status>0</status><categoryLevel>1</categoryLevel></category>
+    </categoryList>
+  </result>
+</result>`))
+	}))
+	defer ts.Close()
+
+	a := NewRMSApi("hoge", "fuga", WithBaseURL(ts.URL))
+	r, err := a.GetCategories()
+	if err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	if len(r.Categories) != 2 {
+		t.Errorf("expected: 2, actual: %d", len(r.Categories))
+		t.FailNow()
+	}
+	if c := r.Find(3); c == nil || c.Name != "チョコレート" || c.CategoryLevel != 3 {
+		t.Errorf("expected: チョコレート, actual: %v", c)
+	}
+	if c := r.Find(5); c != nil {
+		t.Errorf("expected: nil, actual: %v", c)
+	}
+}
+
+func TestInsertCategory_登録とエラー(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		req := CategoryRequest{}
+		xml.Unmarshal(body, &req)
+		if strings.HasSuffix(r.URL.Path, "/insert") {
+			if req.Insert == nil || req.Insert.ParentCategoryID != 1 || req.Insert.Category.Name != "飲料" {
+				t.Errorf("expected: categoryInsertRequest, actual: %s", body)
+			}
+			w.Write([]byte(`<result><resultCode>N000</resultCode><result><categoryId>10</categoryId></result></result>`))
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`<result><resultCode>C008</resultCode><resultMessageList><resultMessage><code>C008</code><message>Requested data is not found.</message></resultMessage></resultMessageList></result>`))
+	}))
+	defer ts.Close()
+
+	a := NewRMSApi("hoge", "fuga", WithBaseURL(ts.URL))
+	id, err := a.InsertCategory(1, &CategoryModel{Name: "飲料"})
+	if err != nil || id != 10 {
+		t.Errorf("expected: 10, actual: %d %v", id, err)
+	}
+	err = a.MoveCategory(99, CATEGORY_ROOT_ID)
+	if !errors.Is(err, &APIError{MessageCode: "C008"}) {
+		t.Errorf("expected: C008, actual: %v", err)
+	}
+}
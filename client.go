@@ -0,0 +1,174 @@
+package rms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	// RMS WEB SERVICEの各エンドポイントのベースURLです。
+	BASE_URL = "https://api.rms.rakuten.co.jp"
+)
+
+// Option は NewRMSApi でRMSApiを生成する際の設定です。
+type Option func(*RMSApi)
+
+// WithHTTPClient はRMSとの通信に使用する *http.Client を指定します。タイムアウトやプロキシの設定はこちらで行ってください。
+func WithHTTPClient(c *http.Client) Option {
+	return func(a *RMSApi) {
+		a.client = c
+	}
+}
+
+// WithTransport はRMSとの通信に使用する http.RoundTripper を指定します。WithHTTPClient と同時に指定した場合、そのクライアントのTransportを置き換えます。
+func WithTransport(rt http.RoundTripper) Option {
+	return func(a *RMSApi) {
+		a.transport = rt
+	}
+}
+
+// WithBaseURL はエンドポイントのベースURLを指定します。検証環境のゲートウェイやテスト用のサーバに接続する場合に使用します。指定しない場合は BASE_URL が使用されます。
+func WithBaseURL(u string) Option {
+	return func(a *RMSApi) {
+		a.baseURL = strings.TrimSuffix(u, "/")
+	}
+}
+
+// NewRMSApi は初期化済みのRMSApiを生成します。ssはサービスシークレット、lkはライセンスキーです。opts で通信に関する設定を変更することができます。
+func NewRMSApi(ss, lk string, opts ...Option) *RMSApi {
+	a := &RMSApi{}
+	for _, opt := range opts {
+		opt(a)
+	}
+	if a.transport != nil {
+		c := http.Client{}
+		if a.client != nil {
+			c = *a.client
+		}
+		c.Transport = a.transport
+		a.client = &c
+	}
+	a.Initialize(ss, lk)
+	return a
+}
+
+// Initialize はSDKを初期化します。ssはサービスシークレット、lkはライセンスキーです。サービスシークレット、ライセンスキーは https://webservice.rms.rakuten.co.jp/merchant-portal/configurationApi のページから確認してください。
+// 初期化後に呼び出すと認証情報を差し替えます。他のgoroutineがAPIを呼び出している間に呼び出しても安全で、差し替え後に送信するリクエストから新しい認証情報が使用されます。
+func (a *RMSApi) Initialize(ss, lk string) {
+	a.credentials.Store(newCredentials(ss, lk))
+}
+
+func (a *RMSApi) httpClient() *http.Client {
+	if a.client != nil {
+		return a.client
+	}
+	return http.DefaultClient
+}
+
+// endpoint はBASE_URLから始まるエンドポイントを、設定されたベースURLのものに置き換えます。
+func (a *RMSApi) endpoint(u string) string {
+	if a.baseURL == "" {
+		return u
+	}
+	return a.baseURL + strings.TrimPrefix(u, BASE_URL)
+}
+
+// postJSON はbodyをJSONに変換してエンドポイントuにPOSTし、レスポンスのステータスコードとボディを返却します。
+func (a *RMSApi) postJSON(ctx context.Context, u string, body interface{}) (int, []byte, error) {
+	jsonStr, err := json.Marshal(body)
+	if err != nil {
+		return 0, nil, err
+	}
+	return a.send(ctx, "POST", u, "application/json; charset=utf-8", nil, jsonStr)
+}
+
+// send はRMSにリクエストを送信し、レスポンスのステータスコードとボディを返却します。ctx がキャンセルされた場合は通信を中断します。
+// レート制限が設定されている場合は送信できるまで待機し、一時的なエラーの場合は再試行の設定に従って再送します。
+func (a *RMSApi) send(ctx context.Context, method, u, contentType string, params url.Values, body []byte) (int, []byte, error) {
+	p := a.retry()
+	attempts := p.attempts(method, u)
+	limiter := a.rateLimiter(u)
+	for n := 1; ; n++ {
+		if limiter != nil {
+			if err := limiter.Wait(ctx); err != nil {
+				return 0, nil, err
+			}
+		}
+		status, b, err := a.sendOnce(ctx, method, u, contentType, params, body)
+		if n >= attempts || !p.shouldRetry(status, b, err) {
+			return status, b, err
+		}
+		if serr := sleep(ctx, p.delay(n)); serr != nil {
+			return status, b, serr
+		}
+	}
+}
+
+// sendStream は body を読み込みながらRMSにリクエストを送信し、レスポンスのステータスコードとボディを返却します。
+// body は一度しか読み込めないため、レート制限には従いますが再試行は行いません。
+func (a *RMSApi) sendStream(ctx context.Context, method, u, contentType string, body io.Reader) (int, []byte, error) {
+	if limiter := a.rateLimiter(u); limiter != nil {
+		if err := limiter.Wait(ctx); err != nil {
+			return 0, nil, err
+		}
+	}
+	return a.do(ctx, method, u, contentType, nil, body)
+}
+
+func (a *RMSApi) sendOnce(ctx context.Context, method, u, contentType string, params url.Values, body []byte) (int, []byte, error) {
+	return a.do(ctx, method, u, contentType, params, bytes.NewReader(body))
+}
+
+func (a *RMSApi) do(ctx context.Context, method, u, contentType string, params url.Values, body io.Reader) (int, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, a.endpoint(u), body)
+	if err != nil {
+		return 0, nil, err
+	}
+	c, err := a.currentCredentials(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("Authorization", "ESA "+c.authorization)
+	req.Header.Set("Content-Type", contentType)
+	if len(params) > 0 {
+		req.URL.RawQuery = params.Encode()
+	}
+
+	resp, err := a.httpClient().Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(resp.Body)
+	return resp.StatusCode, b, err
+}
+
+// sendJSON はJSONで通信するAPIにリクエストを送信し、レスポンスを result に格納します。body が nil の場合はボディを送信せず、result が nil の場合はレスポンスを読み捨てます。
+// 商品API 2.0 等のエラーの形式に従い、ステータスコードが2xx以外の場合は APIError を返却します。
+func (a *RMSApi) sendJSON(ctx context.Context, method, u string, params url.Values, body interface{}, result interface{}) error {
+	var b []byte
+	if body != nil {
+		var err error
+		if b, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+	status, byteArray, err := a.send(ctx, method, u, "application/json; charset=utf-8", params, b)
+	if err != nil {
+		return err
+	}
+	if !isSuccessStatus(status) {
+		return newAPIErrorV2(status, byteArray)
+	}
+	if result == nil || len(byteArray) == 0 {
+		return nil
+	}
+	return json.Unmarshal(byteArray, result)
+}
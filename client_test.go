@@ -0,0 +1,83 @@
+package rms
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestNewRMSApi_ベースURL指定(t *testing.T) {
+	var path, auth string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		auth = r.Header.Get("Authorization")
+		w.Write([]byte(`{"MessageModelList":[{"messageType":"INFO","messageCode":"ORDER_EXT_API_SEARCH_ORDER_INFO_101","message":"注文検索に成功しました。"}],"orderNumberList":[]}`))
+	}))
+	defer ts.Close()
+
+	a := NewRMSApi("hoge", "fuga", WithBaseURL(ts.URL+"/"))
+	_, err := a.SearchOrder(3, time.Now().AddDate(0, 0, -7), time.Now(), nil)
+	if err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	if path != "/es/2.0/order/searchOrder/" {
+		t.Errorf("expected: /es/2.0/order/searchOrder/, actual: %s", path)
+	}
+	if auth != "ESA aG9nZTpmdWdh" {
+		t.Errorf("expected: ESA aG9nZTpmdWdh, actual: %s", auth)
+	}
+}
+
+func TestNewRMSApi_Transport指定(t *testing.T) {
+	var host string
+	rt := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		host = r.URL.Host
+		rec := httptest.NewRecorder()
+		rec.Write([]byte(`<result><resultCode>N000</resultCode></result>`))
+		return rec.Result(), nil
+	})
+
+	a := NewRMSApi("hoge", "fuga", WithHTTPClient(&http.Client{Timeout: time.Second}), WithTransport(rt))
+	_, err := a.GetShopCalendar("", -1)
+	if err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	if host != "api.rms.rakuten.co.jp" {
+		t.Errorf("expected: api.rms.rakuten.co.jp, actual: %s", host)
+	}
+	if a.httpClient().Timeout != time.Second {
+		t.Errorf("expected: %v, actual: %v", time.Second, a.httpClient().Timeout)
+	}
+}
+
+func TestSearchOrderContext_キャンセル(t *testing.T) {
+	done := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer ts.Close()
+	defer close(done)
+
+	a := NewRMSApi("hoge", "fuga", WithBaseURL(ts.URL))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := a.SearchOrderContext(ctx, 3, time.Now().AddDate(0, 0, -7), time.Now(), nil)
+	if err == nil {
+		t.Error("このテストはエラーを発生させるテストですが、エラーは出ませんでした。")
+		t.FailNow()
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected: %v, actual: %v", context.DeadlineExceeded, err)
+	}
+}
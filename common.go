@@ -4,7 +4,7 @@ import "time"
 
 type (
 	// JsonTime はRMS WEB SERVICEで使用する日時をGolangで取り扱えるようにするためのラッパークラスです。
-	// 表示形式はYYYY-MM-DDThh:mm:ss+0900です。
+	// 表示形式はYYYY-MM-DDThh:mm:ss+0900です。JSONに変換する際は日本標準時に変換して出力します。
 	JsonTime struct {
 		time.Time
 	}
@@ -16,8 +16,11 @@ type (
 	}
 )
 
+// jst はRMS WEB SERVICEで使用する日本標準時です。
+var jst = time.FixedZone("Asia/Tokyo", 9*60*60)
+
 func (j JsonTime) format() string {
-	return j.Time.Format("2006-01-02T15:04:05") + "+0900"
+	return j.Time.In(jst).Format("2006-01-02T15:04:05") + "+0900"
 }
 
 // MarshalJSON は値をJSONに変換する際のフォーマット方法を指定します。
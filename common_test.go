@@ -0,0 +1,18 @@
+package rms
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestJsonTime_日本標準時に変換(t *testing.T) {
+	b, err := json.Marshal(JsonTime{time.Date(2020, 4, 30, 15, 30, 0, 0, time.UTC)})
+	if err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	if string(b) != `"2020-05-01T00:30:00+0900"` {
+		t.Errorf("expected: \"2020-05-01T00:30:00+0900\", actual: %s", b)
+	}
+}
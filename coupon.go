@@ -0,0 +1,437 @@
+package rms
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	// RMS WEB SERVICEのクーポンAPIのクーポン発行用のエンドポイントです。
+	COUPON_ISSUE_URL = "https://api.rms.rakuten.co.jp/es/1.0/coupon/issue"
+
+	// RMS WEB SERVICEのクーポンAPIのクーポン更新用のエンドポイントです。
+	COUPON_UPDATE_URL = "https://api.rms.rakuten.co.jp/es/1.0/coupon/update"
+
+	// RMS WEB SERVICEのクーポンAPIのクーポン削除用のエンドポイントです。
+	COUPON_DELETE_URL = "https://api.rms.rakuten.co.jp/es/1.0/coupon/delete"
+
+	// RMS WEB SERVICEのクーポンAPIのクーポン取得用のエンドポイントです。
+	COUPON_GET_URL = "https://api.rms.rakuten.co.jp/es/1.0/coupon/get"
+
+	// RMS WEB SERVICEのクーポンAPIのクーポン検索用のエンドポイントです。
+	COUPON_SEARCH_URL = "https://api.rms.rakuten.co.jp/es/1.0/coupon/search"
+
+	// COUPON_SEARCH_MAX_HITS はクーポン検索で1ページに取得できる最大の件数です。
+	COUPON_SEARCH_MAX_HITS = 100
+
+	// COUPON_RATE_DISCOUNT_MAX は定率値引きで指定できる最大の割引率(%)です。
+	COUPON_RATE_DISCOUNT_MAX = 99
+
+	// COUPON_DATE_FORMAT はクーポンの利用開始日時・利用終了日時の形式です。
+	COUPON_DATE_FORMAT = "2006-01-02T15:04:05+09:00"
+)
+
+// CouponCapitalCode はクーポン原資を表します。GetOrderCouponModel の CouponCapitalCode と同じ値です。
+type CouponCapitalCode int
+
+const (
+	COUPON_CAPITAL_CODE_SHOP    CouponCapitalCode = 1 // ショップ
+	COUPON_CAPITAL_CODE_MAKER   CouponCapitalCode = 2 // メーカー
+	COUPON_CAPITAL_CODE_SERVICE CouponCapitalCode = 3 // サービス
+)
+
+// CouponDiscountType はクーポンの割引の種類を表します。種類によって DiscountFactor の単位が異なります。
+type CouponDiscountType int
+
+const (
+	COUPON_DISCOUNT_TYPE_FIXED_AMOUNT  CouponDiscountType = 1 // 定額値引き。DiscountFactor は円です。
+	COUPON_DISCOUNT_TYPE_RATE          CouponDiscountType = 2 // 定率値引き。DiscountFactor は%です。
+	COUPON_DISCOUNT_TYPE_FREE_SHIPPING CouponDiscountType = 4 // 送料無料。DiscountFactor は指定しません。
+)
+
+// CouponItemType はクーポンの対象商品の指定方法を表します。
+type CouponItemType int
+
+const (
+	COUPON_ITEM_TYPE_SINGLE   CouponItemType = 1 // 単一商品
+	COUPON_ITEM_TYPE_MULTIPLE CouponItemType = 2 // 複数商品
+	COUPON_ITEM_TYPE_ALL      CouponItemType = 3 // 店舗内の全商品
+)
+
+// CouponConditionType はクーポンの利用条件の種別を表します。
+type CouponConditionType string
+
+const (
+	COUPON_CONDITION_TYPE_PURCHASE_AMOUNT   CouponConditionType = "RS001" // 購入金額(円)
+	COUPON_CONDITION_TYPE_PURCHASE_QUANTITY CouponConditionType = "RS002" // 購入個数
+)
+
+type (
+	// CouponTime はクーポンAPIで使用する日時をGolangで取り扱えるようにするためのラッパークラスです。
+	// 表示形式はYYYY-MM-DDThh:mm:ss+09:00です。XMLに変換する際は日本標準時に変換して出力します。
+	CouponTime struct {
+		time.Time
+	}
+
+	// CouponItemModel はクーポンの対象商品です。
+	CouponItemModel struct {
+		// ItemURL は商品管理番号です。
+		ItemURL string `xml:"itemUrl"`
+	}
+
+	// CouponConditionModel はクーポンの利用条件です。
+	CouponConditionModel struct {
+		// ConditionTypeCode は利用条件の種別です。
+		ConditionTypeCode CouponConditionType `xml:"conditionTypeCode"`
+
+		// StartValue は条件の下限値です。
+		StartValue int `xml:"startValue"`
+
+		// EndValue は条件の上限値です。0の場合は上限を設けません。
+		EndValue int `xml:"endValue,omitempty"`
+	}
+
+	// CouponModel はクーポンAPIで取り扱うクーポンです。
+	CouponModel struct {
+		// CouponCode はクーポンコードです。発行時は指定せず、発行後に採番されます。
+		CouponCode string `xml:"couponCode,omitempty"`
+
+		// CouponName はクーポン名です。
+		CouponName string `xml:"couponName"`
+
+		// CouponCaption はクーポンの説明文です。
+		CouponCaption string `xml:"couponCaption,omitempty"`
+
+		// CouponStartDate は利用開始日時です。
+		CouponStartDate CouponTime `xml:"couponStartDate"`
+
+		// CouponEndDate は利用終了日時です。
+		CouponEndDate CouponTime `xml:"couponEndDate"`
+
+		// CouponCapitalCode はクーポン原資です。店舗が発行できるのは COUPON_CAPITAL_CODE_SHOP のみで、指定しない場合もショップとして扱われます。
+		CouponCapitalCode CouponCapitalCode `xml:"couponCapitalCode,omitempty"`
+
+		// ItemType は対象商品の指定方法です。
+		ItemType CouponItemType `xml:"itemType"`
+
+		// Items は対象商品です。ItemType が COUPON_ITEM_TYPE_ALL の場合は指定しません。
+		Items []CouponItemModel `xml:"items>item,omitempty"`
+
+		// DiscountType は割引の種類です。
+		DiscountType CouponDiscountType `xml:"discountType"`
+
+		// DiscountFactor は割引額(円)もしくは割引率(%)です。単位は DiscountType によって異なります。
+		DiscountFactor int `xml:"discountFactor,omitempty"`
+
+		// MemberAvailMaxCount は1会員あたりの利用上限回数です。0の場合は上限を設けません。
+		MemberAvailMaxCount int `xml:"memberAvailMaxCount,omitempty"`
+
+		// CouponConditions は利用条件です。
+		CouponConditions []CouponConditionModel `xml:"couponConditions>couponCondition,omitempty"`
+	}
+
+	// CouponDeleteCondition はクーポンAPIのクーポン削除の条件です。
+	CouponDeleteCondition struct {
+		// CouponCode はクーポンコードです。
+		CouponCode string `xml:"couponCode"`
+	}
+
+	// CouponRequest はクーポンAPIのクーポン発行・更新・削除のリクエストです。
+	CouponRequest struct {
+		XMLName xml.Name `xml:"request"`
+
+		// Issue は発行するクーポンです。
+		Issue *CouponModel `xml:"couponIssueRequest>coupon,omitempty"`
+
+		// Update は更新するクーポンです。
+		Update *CouponModel `xml:"couponUpdateRequest>coupon,omitempty"`
+
+		// Delete は削除するクーポンです。
+		Delete *CouponDeleteCondition `xml:"couponDeleteRequest>coupon,omitempty"`
+	}
+
+	// CouponIssueResult はクーポンAPIのクーポン発行の処理結果です。
+	CouponIssueResult struct {
+		ItemResult
+
+		// CouponCode は採番されたクーポンコードです。
+		CouponCode string `xml:"couponCode"`
+	}
+
+	// CouponUpdateResponse はクーポンAPIのクーポン発行・更新・削除で得られるレスポンスです。
+	CouponUpdateResponse struct {
+		// Status は処理状況です。
+		Status ItemApiStatus `xml:"status"`
+
+		// IssueResult はクーポン発行の処理結果です。
+		IssueResult *CouponIssueResult `xml:"couponIssueResult"`
+
+		// UpdateResult はクーポン更新の処理結果です。
+		UpdateResult *ItemResult `xml:"couponUpdateResult"`
+
+		// DeleteResult はクーポン削除の処理結果です。
+		DeleteResult *ItemResult `xml:"couponDeleteResult"`
+	}
+
+	// CouponGetResult はクーポンAPIのクーポン取得の処理結果です。
+	CouponGetResult struct {
+		ItemResult
+
+		// Coupon はクーポンです。
+		Coupon CouponModel `xml:"coupon"`
+	}
+
+	// CouponGetResponse はクーポンAPIのクーポン取得で得られるレスポンスです。
+	CouponGetResponse struct {
+		// Status は処理状況です。
+		Status ItemApiStatus `xml:"status"`
+
+		// Result は処理結果です。
+		Result CouponGetResult `xml:"couponGetResult"`
+	}
+
+	// CouponSearchCondition はクーポンAPIのクーポン検索の条件です。指定しない項目は条件に含まれません。
+	CouponSearchCondition struct {
+		// CouponName はクーポン名です。部分一致で検索します。
+		CouponName string
+
+		// CouponCode はクーポンコードです。
+		CouponCode string
+
+		// ItemURL は対象商品の商品管理番号です。
+		ItemURL string
+
+		// Page は取得するページです。1から始まります。0の場合は1ページ目を取得します。
+		Page int
+
+		// Hits は1ページあたりの件数です。1~100まで指定することができます。それ以外の場合は100件取得します。
+		Hits int
+	}
+
+	// CouponSearchResult はクーポンAPIのクーポン検索の処理結果です。
+	CouponSearchResult struct {
+		ItemResult
+
+		// NumFound は条件に一致したクーポンの数です。
+		NumFound int `xml:"numFound"`
+
+		// Coupons はクーポンです。
+		Coupons []CouponModel `xml:"coupons>coupon"`
+	}
+
+	// CouponSearchResponse はクーポンAPIのクーポン検索で得られるレスポンスです。
+	CouponSearchResponse struct {
+		// Status は処理状況です。
+		Status ItemApiStatus `xml:"status"`
+
+		// Result は処理結果です。
+		Result CouponSearchResult `xml:"couponSearchResult"`
+	}
+)
+
+// MarshalXML は値をXMLに変換する際のフォーマット方法を指定します。
+func (t CouponTime) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(t.Time.In(jst).Format(COUPON_DATE_FORMAT), start)
+}
+
+// UnmarshalXML はXMLの値をGoの型に変換する際の変換方法を指定します。値が空の場合はゼロ値のままです。
+func (t *CouponTime) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var s string
+	if err := d.DecodeElement(&s, &start); err != nil {
+		return err
+	}
+	if s == "" {
+		*t = CouponTime{}
+		return nil
+	}
+	v, err := time.Parse(time.RFC3339, s)
+	*t = CouponTime{v}
+	return err
+}
+
+// IssueCoupon はクーポンAPIでクーポンを発行し、採番されたクーポンコードを返却します。送信前に validate で利用期間や割引、利用条件を検証します。
+func (a *RMSApi) IssueCoupon(c *CouponModel) (string, error) {
+	return a.IssueCouponContext(context.Background(), c)
+}
+
+// IssueCouponContext は IssueCoupon にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) IssueCouponContext(ctx context.Context, c *CouponModel) (string, error) {
+	if !a.initialized() {
+		return "", ErrNotInitialized
+	}
+	if err := c.validate(); err != nil {
+		return "", err
+	}
+	issue := *c
+	issue.CouponCode = ""
+	result := CouponUpdateResponse{IssueResult: &CouponIssueResult{}}
+	if err := a.sendItemXML(ctx, "POST", COUPON_ISSUE_URL, nil, &CouponRequest{Issue: &issue}, &result, &result.IssueResult.ItemResult); err != nil {
+		return "", err
+	}
+	return result.IssueResult.CouponCode, nil
+}
+
+// UpdateCoupon はクーポンAPIでクーポンを更新します。c の CouponCode で対象のクーポンを指定します。
+func (a *RMSApi) UpdateCoupon(c *CouponModel) error {
+	return a.UpdateCouponContext(context.Background(), c)
+}
+
+// UpdateCouponContext は UpdateCoupon にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) UpdateCouponContext(ctx context.Context, c *CouponModel) error {
+	if !a.initialized() {
+		return ErrNotInitialized
+	}
+	if c.CouponCode == "" {
+		return &ValidationError{Field: "couponCode", Message: "クーポンコードを指定してください。"}
+	}
+	if err := c.validate(); err != nil {
+		return err
+	}
+	result := CouponUpdateResponse{UpdateResult: &ItemResult{}}
+	return a.sendItemXML(ctx, "POST", COUPON_UPDATE_URL, nil, &CouponRequest{Update: c}, &result, result.UpdateResult)
+}
+
+// DeleteCoupon はクーポンAPIでクーポンを削除します。
+func (a *RMSApi) DeleteCoupon(couponCode string) error {
+	return a.DeleteCouponContext(context.Background(), couponCode)
+}
+
+// DeleteCouponContext は DeleteCoupon にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) DeleteCouponContext(ctx context.Context, couponCode string) error {
+	if !a.initialized() {
+		return ErrNotInitialized
+	}
+	result := CouponUpdateResponse{DeleteResult: &ItemResult{}}
+	return a.sendItemXML(ctx, "POST", COUPON_DELETE_URL, nil, &CouponRequest{Delete: &CouponDeleteCondition{couponCode}}, &result, result.DeleteResult)
+}
+
+// GetCoupon はクーポンAPIでクーポンを取得します。
+func (a *RMSApi) GetCoupon(couponCode string) (*CouponModel, error) {
+	return a.GetCouponContext(context.Background(), couponCode)
+}
+
+// GetCouponContext は GetCoupon にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) GetCouponContext(ctx context.Context, couponCode string) (*CouponModel, error) {
+	if !a.initialized() {
+		return nil, ErrNotInitialized
+	}
+	params := url.Values{}
+	params.Add("couponCode", couponCode)
+	result := CouponGetResponse{}
+	if err := a.sendItemXML(ctx, "GET", COUPON_GET_URL, params, nil, &result, &result.Result.ItemResult); err != nil {
+		return nil, err
+	}
+	return &result.Result.Coupon, nil
+}
+
+// SearchCoupon はクーポンAPIでクーポンを検索します。cond は検索条件です。
+func (a *RMSApi) SearchCoupon(cond *CouponSearchCondition) (*CouponSearchResult, error) {
+	return a.SearchCouponContext(context.Background(), cond)
+}
+
+// SearchCouponContext は SearchCoupon にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) SearchCouponContext(ctx context.Context, cond *CouponSearchCondition) (*CouponSearchResult, error) {
+	if !a.initialized() {
+		return nil, ErrNotInitialized
+	}
+	if cond == nil {
+		cond = &CouponSearchCondition{}
+	}
+	params := url.Values{}
+	if cond.CouponName != "" {
+		params.Add("couponName", cond.CouponName)
+	}
+	if cond.CouponCode != "" {
+		params.Add("couponCode", cond.CouponCode)
+	}
+	if cond.ItemURL != "" {
+		params.Add("itemUrl", cond.ItemURL)
+	}
+	page := cond.Page
+	if page <= 0 {
+		page = 1
+	}
+	params.Add("page", fmt.Sprintf("%d", page))
+	hits := cond.Hits
+	if hits <= 0 || hits > COUPON_SEARCH_MAX_HITS {
+		hits = COUPON_SEARCH_MAX_HITS
+	}
+	params.Add("hits", fmt.Sprintf("%d", hits))
+
+	result := CouponSearchResponse{}
+	if err := a.sendItemXML(ctx, "GET", COUPON_SEARCH_URL, params, nil, &result, &result.Result.ItemResult); err != nil {
+		return nil, err
+	}
+	return &result.Result, nil
+}
+
+// validate は送信前にクーポンの利用期間、対象商品、割引、利用条件を検証します。
+func (c *CouponModel) validate() error {
+	if c.CouponName == "" {
+		return &ValidationError{Field: "couponName", Message: "クーポン名を指定してください。"}
+	}
+	if c.CouponStartDate.IsZero() || c.CouponEndDate.IsZero() {
+		return &ValidationError{Field: "couponStartDate", Message: "利用開始日時と利用終了日時を指定してください。"}
+	}
+	if !c.CouponEndDate.After(c.CouponStartDate.Time) {
+		return &ValidationError{Field: "couponEndDate", Message: "利用終了日時は利用開始日時より後でなければいけません。"}
+	}
+	if c.CouponCapitalCode != 0 && c.CouponCapitalCode != COUPON_CAPITAL_CODE_SHOP {
+		return &ValidationError{Field: "couponCapitalCode", Message: "店舗が発行できるのはショップ原資のクーポンのみです。"}
+	}
+
+	switch c.ItemType {
+	case COUPON_ITEM_TYPE_SINGLE:
+		if len(c.Items) != 1 {
+			return &ValidationError{Field: "items", Message: "単一商品のクーポンは対象商品を1件指定してください。"}
+		}
+	case COUPON_ITEM_TYPE_MULTIPLE:
+		if len(c.Items) == 0 {
+			return &ValidationError{Field: "items", Message: "複数商品のクーポンは対象商品を1件以上指定してください。"}
+		}
+	case COUPON_ITEM_TYPE_ALL:
+		if len(c.Items) != 0 {
+			return &ValidationError{Field: "items", Message: "全商品のクーポンに対象商品は指定できません。"}
+		}
+	default:
+		return &ValidationError{Field: "itemType", Message: "対象商品の指定方法が不正です。"}
+	}
+
+	switch c.DiscountType {
+	case COUPON_DISCOUNT_TYPE_FIXED_AMOUNT:
+		if c.DiscountFactor < 1 {
+			return &ValidationError{Field: "discountFactor", Message: "定額値引きの割引額は1円以上でなければいけません。"}
+		}
+	case COUPON_DISCOUNT_TYPE_RATE:
+		if c.DiscountFactor < 1 || c.DiscountFactor > COUPON_RATE_DISCOUNT_MAX {
+			return &ValidationError{Field: "discountFactor", Message: "定率値引きの割引率は1%以上99%以下でなければいけません。"}
+		}
+	case COUPON_DISCOUNT_TYPE_FREE_SHIPPING:
+		if c.DiscountFactor != 0 {
+			return &ValidationError{Field: "discountFactor", Message: "送料無料のクーポンに割引額は指定できません。"}
+		}
+	default:
+		return &ValidationError{Field: "discountType", Message: "割引の種類が不正です。"}
+	}
+
+	if c.MemberAvailMaxCount < 0 {
+		return &ValidationError{Field: "memberAvailMaxCount", Message: "利用上限回数は0以上でなければいけません。"}
+	}
+	for _, cond := range c.CouponConditions {
+		switch cond.ConditionTypeCode {
+		case COUPON_CONDITION_TYPE_PURCHASE_AMOUNT, COUPON_CONDITION_TYPE_PURCHASE_QUANTITY:
+		default:
+			return &ValidationError{Field: "conditionTypeCode", Message: fmt.Sprintf("利用条件の種別 %s が不正です。", cond.ConditionTypeCode)}
+		}
+		if cond.StartValue < 0 || (cond.EndValue != 0 && cond.EndValue < cond.StartValue) {
+			return &ValidationError{Field: "startValue", Message: "利用条件の上限値は下限値以上でなければいけません。"}
+		}
+		if cond.ConditionTypeCode == COUPON_CONDITION_TYPE_PURCHASE_AMOUNT && c.DiscountType == COUPON_DISCOUNT_TYPE_FIXED_AMOUNT && c.DiscountFactor >= cond.StartValue {
+			return &ValidationError{Field: "discountFactor", Message: "定額値引きの割引額は利用条件の購入金額の下限より小さくなければいけません。"}
+		}
+	}
+	return nil
+}
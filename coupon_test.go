@@ -0,0 +1,99 @@
+package rms
+
+import (
+	"encoding/xml"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestCoupon() *CouponModel {
+	start := time.Date(2020, 5, 1, 0, 0, 0, 0, jst)
+	return &CouponModel{
+		CouponName:       "ゴールデンウィーク500円OFF",
+		CouponStartDate:  CouponTime{start},
+		CouponEndDate:    CouponTime{start.AddDate(0, 0, 7)},
+		ItemType:         COUPON_ITEM_TYPE_ALL,
+		DiscountType:     COUPON_DISCOUNT_TYPE_FIXED_AMOUNT,
+		DiscountFactor:   500,
+		CouponConditions: []CouponConditionModel{{ConditionTypeCode: COUPON_CONDITION_TYPE_PURCHASE_AMOUNT, StartValue: 3000}},
+	}
+}
+
+func TestIssueCoupon_発行(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		req := CouponRequest{}
+		if err := xml.Unmarshal(body, &req); err != nil || req.Issue == nil || req.Issue.DiscountFactor != 500 || !req.Issue.CouponStartDate.Equal(time.Date(2020, 5, 1, 0, 0, 0, 0, jst)) {
+			t.Errorf("expected: couponIssueRequest, actual: %s", body)
+		}
+		w.Write([]byte(`<result><status><systemStatus>OK</systemStatus><message>OK</message></status><couponIssueResult><code>N000</code><couponCode>ABCD-EFGH-IJKL-MNOP</couponCode></couponIssueResult></result>`))
+	}))
+	defer ts.Close()
+
+	a := NewRMSApi("hoge", "fuga", WithBaseURL(ts.URL))
+	code, err := a.IssueCoupon(newTestCoupon())
+	if err != nil || code != "ABCD-EFGH-IJKL-MNOP" {
+		t.Errorf("expected: ABCD-EFGH-IJKL-MNOP, actual: %s %v", code, err)
+	}
+}
+
+func TestIssueCoupon_UTCの日時(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		for _, s := range []string{"<couponStartDate>2020-05-01T00:00:00+09:00</couponStartDate>", "<couponEndDate>2020-05-08T00:00:00+09:00</couponEndDate>"} {
+			if !strings.Contains(string(body), s) {
+				t.Errorf("expected: %s, actual: %s", s, body)
+			}
+		}
+		w.Write([]byte(`<result><status><systemStatus>OK</systemStatus><message>OK</message></status><couponIssueResult><code>N000</code><couponCode>ABCD-EFGH-IJKL-MNOP</couponCode></couponIssueResult></result>`))
+	}))
+	defer ts.Close()
+
+	c := newTestCoupon()
+	start := time.Date(2020, 4, 30, 15, 0, 0, 500, time.UTC)
+	c.CouponStartDate = CouponTime{start}
+	c.CouponEndDate = CouponTime{start.AddDate(0, 0, 7)}
+	a := NewRMSApi("hoge", "fuga", WithBaseURL(ts.URL))
+	if _, err := a.IssueCoupon(c); err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+	}
+}
+
+func TestIssueCoupon_入力値の検証(t *testing.T) {
+	cases := map[string]func(c *CouponModel){
+		"期間が逆":     func(c *CouponModel) { c.CouponEndDate = CouponTime{c.CouponStartDate.Add(-time.Hour)} },
+		"定率の上限超え":  func(c *CouponModel) { c.DiscountType = COUPON_DISCOUNT_TYPE_RATE; c.DiscountFactor = 100 },
+		"利用条件より高額": func(c *CouponModel) { c.DiscountFactor = 3000 },
+		"対象商品の不足":  func(c *CouponModel) { c.ItemType = COUPON_ITEM_TYPE_SINGLE },
+		"メーカー原資":   func(c *CouponModel) { c.CouponCapitalCode = COUPON_CAPITAL_CODE_MAKER },
+	}
+	a := NewRMSApi("hoge", "fuga", WithBaseURL("http://127.0.0.1:0"))
+	for name, f := range cases {
+		c := newTestCoupon()
+		f(c)
+		if _, err := a.IssueCoupon(c); !errors.As(err, new(*ValidationError)) {
+			t.Errorf("%s expected: ValidationError, actual: %v", name, err)
+		}
+	}
+}
+
+func TestSearchCoupon_エラー(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("hits") != "100" || r.URL.Query().Get("page") != "1" {
+			t.Errorf("expected: hits=100&page=1, actual: %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(`<result><status><systemStatus>NG</systemStatus><message>NG</message></status><couponSearchResult><code>C001</code><errorMessages><errorMessage><fieldId>couponName</fieldId><msgCode>E01</msgCode><msg>invalid</msg></errorMessage></errorMessages></couponSearchResult></result>`))
+	}))
+	defer ts.Close()
+
+	a := NewRMSApi("hoge", "fuga", WithBaseURL(ts.URL))
+	_, err := a.SearchCoupon(nil)
+	if !errors.Is(err, &APIError{MessageCode: "C001"}) {
+		t.Errorf("expected: C001, actual: %v", err)
+	}
+}
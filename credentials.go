@@ -0,0 +1,87 @@
+package rms
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+)
+
+type (
+	// CredentialsProvider はRMSとの通信に使用するサービスシークレットとライセンスキーを提供します。WithCredentialsProvider で指定します。
+	// Credentials はリクエストを送信するたびに呼び出され、複数のgoroutineから同時に呼び出されることがあります。
+	// シークレットストア等から取得する場合は、実装側でキャッシュしてください。
+	CredentialsProvider interface {
+		Credentials(ctx context.Context) (serviceSecret, licenseKey string, err error)
+	}
+
+	// CredentialsProviderFunc は関数を CredentialsProvider として使用するためのアダプタです。
+	CredentialsProviderFunc func(ctx context.Context) (serviceSecret, licenseKey string, err error)
+
+	// credentials はESA認証に使用する認証情報です。差し替える場合は新しい値を生成し、既存の値は書き換えません。
+	credentials struct {
+		serviceSecret string
+		licenseKey    string
+		authorization string
+	}
+
+	// credentialsKey は withCredentials で固定した認証情報をコンテキストに格納する際のキーです。
+	credentialsKey struct{}
+)
+
+// Credentials は f(ctx) を呼び出します。
+func (f CredentialsProviderFunc) Credentials(ctx context.Context) (string, string, error) {
+	return f(ctx)
+}
+
+// WithCredentialsProvider は認証情報を p から取得するように指定します。指定した場合、NewRMSApi や Initialize で指定したサービスシークレットとライセンスキーは使用されません。
+// ライセンスキーの更新に合わせて、ワーカーを再起動せずに認証情報を差し替える場合に使用します。
+func WithCredentialsProvider(p CredentialsProvider) Option {
+	return func(a *RMSApi) {
+		a.credentialsProvider = p
+	}
+}
+
+func newCredentials(ss, lk string) *credentials {
+	return &credentials{
+		serviceSecret: ss,
+		licenseKey:    lk,
+		authorization: base64.StdEncoding.EncodeToString([]byte(ss + ":" + lk)),
+	}
+}
+
+// initialized は Initialize で初期化されているか、CredentialsProvider が指定されているかを返却します。
+func (a *RMSApi) initialized() bool {
+	if a.credentialsProvider != nil {
+		return true
+	}
+	_, ok := a.credentials.Load().(*credentials)
+	return ok
+}
+
+// withCredentials は ctx を使用したリクエストで c を認証情報として使用するように固定します。
+// リクエストの内容に認証情報を含める場合に、送信までの間に認証情報が差し替えられても同じ認証情報で認証するために使用します。
+func withCredentials(ctx context.Context, c *credentials) context.Context {
+	return context.WithValue(ctx, credentialsKey{}, c)
+}
+
+// currentCredentials は現在の認証情報を返却します。withCredentials で固定されている場合はその認証情報を、CredentialsProvider が指定されている場合はそちらから取得します。
+func (a *RMSApi) currentCredentials(ctx context.Context) (*credentials, error) {
+	if c, ok := ctx.Value(credentialsKey{}).(*credentials); ok {
+		return c, nil
+	}
+	if a.credentialsProvider != nil {
+		ss, lk, err := a.credentialsProvider.Credentials(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get credentials: %w", err)
+		}
+		if ss == "" || lk == "" {
+			return nil, fmt.Errorf("%w: credentials provider returned an empty service secret or license key", ErrNotInitialized)
+		}
+		return newCredentials(ss, lk), nil
+	}
+	c, ok := a.credentials.Load().(*credentials)
+	if !ok {
+		return nil, ErrNotInitialized
+	}
+	return c, nil
+}
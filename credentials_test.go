@@ -0,0 +1,124 @@
+package rms
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newLicenseTestServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lk := r.URL.Query().Get("licenseKey")
+		if r.Header.Get("Authorization") != "ESA "+base64.StdEncoding.EncodeToString([]byte("hoge:"+lk)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"errors":[{"code":"GA0001","message":"Unauthorized"}]}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		if lk == "new" {
+			w.Write([]byte(`{"expiryDate":"2021-01-31"}`))
+			return
+		}
+		w.Write([]byte(`{"expiryDate":"2020-07-31"}`))
+	}))
+}
+
+func TestGetLicenseKeyExpiryDate_有効期限の取得(t *testing.T) {
+	ts := newLicenseTestServer(t)
+	defer ts.Close()
+
+	a := NewRMSApi("hoge", "old", WithBaseURL(ts.URL))
+	d, err := a.GetLicenseKeyExpiryDate()
+	if err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	if d.Format("2006-01-02") != "2020-07-31" {
+		t.Errorf("expected: 2020-07-31, actual: %v", d)
+	}
+
+	a.Initialize("hoge", "new")
+	d, err = a.GetLicenseKeyExpiryDate()
+	if err != nil || d.Format("2006-01-02") != "2021-01-31" {
+		t.Errorf("expected: 2021-01-31, actual: %v %v", d, err)
+	}
+}
+
+func TestCredentialsProvider_実行中の差し替え(t *testing.T) {
+	ts := newLicenseTestServer(t)
+	defer ts.Close()
+
+	var key atomic.Value
+	key.Store("old")
+	p := CredentialsProviderFunc(func(ctx context.Context) (string, string, error) {
+		return "hoge", key.Load().(string), nil
+	})
+	a := NewRMSApi("", "", WithBaseURL(ts.URL), WithCredentialsProvider(p))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i == 4 {
+				key.Store("new")
+			}
+			if _, err := a.GetLicenseKeyExpiryDate(); err != nil {
+				t.Errorf("Happend undefined error: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	d, _ := a.GetLicenseKeyExpiryDate()
+	if d.Format("2006-01-02") != "2021-01-31" {
+		t.Errorf("expected: 2021-01-31, actual: %v", d)
+	}
+}
+
+func TestCredentialsProvider_取得のたびに差し替え(t *testing.T) {
+	ts := newLicenseTestServer(t)
+	defer ts.Close()
+
+	var calls int32
+	p := CredentialsProviderFunc(func(ctx context.Context) (string, string, error) {
+		if atomic.AddInt32(&calls, 1)%2 == 0 {
+			return "hoge", "new", nil
+		}
+		return "hoge", "old", nil
+	})
+	a := NewRMSApi("", "", WithBaseURL(ts.URL), WithCredentialsProvider(p))
+	for i := 0; i < 4; i++ {
+		if _, err := a.GetLicenseKeyExpiryDate(); err != nil {
+			t.Errorf("Happend undefined error: %v", err)
+		}
+	}
+	if calls != 4 {
+		t.Errorf("expected: 4, actual: %d", calls)
+	}
+}
+
+func TestCredentialsProvider_取得失敗(t *testing.T) {
+	failed := errors.New("secret store is unavailable")
+	a := NewRMSApi("", "", WithBaseURL("http://127.0.0.1:0"), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}), WithCredentialsProvider(CredentialsProviderFunc(func(ctx context.Context) (string, string, error) {
+		return "", "", failed
+	})))
+	if _, err := a.GetLicenseKeyExpiryDate(); !errors.Is(err, failed) {
+		t.Errorf("expected: %v, actual: %v", failed, err)
+	}
+
+	empty := NewRMSApi("", "", WithCredentialsProvider(CredentialsProviderFunc(func(ctx context.Context) (string, string, error) {
+		return "", "", nil
+	})))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := empty.GetLicenseKeyExpiryDateContext(ctx); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("expected: %v, actual: %v", ErrNotInitialized, err)
+	}
+}
@@ -0,0 +1,246 @@
+package rms
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// SHIPPING_DEADLINE_DEFAULT_ASURAKU_CUTOFF は ShippingDeadlineRule で指定しない場合のあす楽の当日発送の締め時刻です。0時からの経過時間で表します。
+	SHIPPING_DEADLINE_DEFAULT_ASURAKU_CUTOFF = 12 * time.Hour
+
+	// SHIPPING_DEADLINE_DEFAULT_TRANSIT_DAYS は ShippingDeadlineRule で指定しない場合の、発送からお届けまでにかかる日数です。
+	SHIPPING_DEADLINE_DEFAULT_TRANSIT_DAYS = 1
+
+	// SHIPPING_DEADLINE_DEFAULT_LEAD_DAYS は ShippingDeadlineRule で指定せず、納期情報からも読み取れない場合の発送までの営業日数です。
+	SHIPPING_DEADLINE_DEFAULT_LEAD_DAYS = 3
+
+	// SHIPPING_DEADLINE_CALENDAR_PERIOD は GetShippingDeadlines で取得する営業日カレンダーの日数です。
+	SHIPPING_DEADLINE_CALENDAR_PERIOD = 180
+)
+
+// ShippingDeadlineReason は発送期限の根拠を表します。
+type ShippingDeadlineReason int
+
+const (
+	// SHIPPING_DEADLINE_REASON_DELIVERY_DATE はお届け日指定から逆算した期限です。
+	SHIPPING_DEADLINE_REASON_DELIVERY_DATE ShippingDeadlineReason = iota + 1
+
+	// SHIPPING_DEADLINE_REASON_ASURAKU はあす楽の締め時刻による期限です。
+	SHIPPING_DEADLINE_REASON_ASURAKU
+
+	// SHIPPING_DEADLINE_REASON_DELVDATE_INFO は商品の納期情報による期限です。
+	SHIPPING_DEADLINE_REASON_DELVDATE_INFO
+
+	// SHIPPING_DEADLINE_REASON_DEFAULT は ShippingDeadlineRule の LeadDays による期限です。
+	SHIPPING_DEADLINE_REASON_DEFAULT
+)
+
+// delvdateInfoPattern は「1～2日以内に発送予定」「3営業日以内に発送」のような納期情報から、発送までの日数の上限を読み取るためのパターンです。
+var delvdateInfoPattern = regexp.MustCompile(`([0-9]+)(?:営業)?日以内`)
+
+// fullWidthDigits は全角数字を半角数字に変換します。
+var fullWidthDigits = strings.NewReplacer("０", "0", "１", "1", "２", "2", "３", "3", "４", "4", "５", "5", "６", "6", "７", "7", "８", "8", "９", "9")
+
+type (
+	// ShippingDeadlineRule は発送期限を計算する際の店舗ごとの設定です。0の項目は既定値を使用します。
+	ShippingDeadlineRule struct {
+		// AsurakuCutoff はあす楽の当日発送の締め時刻です。0時からの経過時間で指定します。指定しない場合は SHIPPING_DEADLINE_DEFAULT_ASURAKU_CUTOFF です。
+		AsurakuCutoff time.Duration
+
+		// TransitDays はお届け日指定の注文で、発送からお届けまでにかかる日数です。指定しない場合は SHIPPING_DEADLINE_DEFAULT_TRANSIT_DAYS です。
+		TransitDays int
+
+		// LeadDays は納期情報から発送までの日数を読み取れない場合の、注文日から発送までの営業日数です。指定しない場合は SHIPPING_DEADLINE_DEFAULT_LEAD_DAYS です。
+		LeadDays int
+
+		// AtRiskShippingDays は期限の何発送日前から AtRisk とするかです。0の場合は期限の当日から AtRisk とします。
+		AtRiskShippingDays int
+	}
+
+	// ShippingDeadline は注文ごとの発送期限です。
+	ShippingDeadline struct {
+		// OrderNumber は注文番号です。
+		OrderNumber string
+
+		// Deadline は発送しなければならない最終日です。日本標準時の0時で表します。
+		Deadline time.Time
+
+		// Reason は期限の根拠です。
+		Reason ShippingDeadlineReason
+
+		// Shipped は発送済み、もしくはキャンセル等で発送が不要な注文かどうかです。
+		Shipped bool
+
+		// AtRisk は未発送の注文のうち、期限が迫っているか過ぎているかどうかです。
+		AtRisk bool
+
+		// Overdue は未発送のまま期限を過ぎているかどうかです。
+		Overdue bool
+	}
+)
+
+// GetShippingDeadlines は営業日カレンダーを取得し、orders の注文ごとの発送期限を計算します。now は AtRisk、Overdue の判定に使用する現在日時です。
+// 営業日カレンダーは注文日のうち最も古い日から180日分を取得します。
+func (a *RMSApi) GetShippingDeadlines(orders []GetOrderOrderModel, rule *ShippingDeadlineRule, now time.Time) ([]ShippingDeadline, error) {
+	return a.GetShippingDeadlinesContext(context.Background(), orders, rule, now)
+}
+
+// GetShippingDeadlinesContext は GetShippingDeadlines にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) GetShippingDeadlinesContext(ctx context.Context, orders []GetOrderOrderModel, rule *ShippingDeadlineRule, now time.Time) ([]ShippingDeadline, error) {
+	if !a.initialized() {
+		return nil, ErrNotInitialized
+	}
+	if len(orders) == 0 {
+		return []ShippingDeadline{}, nil
+	}
+	from := orders[0].OrderDatetime.Time
+	for _, o := range orders {
+		if o.OrderDatetime.Time.Before(from) {
+			from = o.OrderDatetime.Time
+		}
+	}
+	r, err := a.GetShopCalendarContext(ctx, from.In(jst).Format("2006-01-02"), SHIPPING_DEADLINE_CALENDAR_PERIOD)
+	if err != nil {
+		return nil, err
+	}
+	c := &ShopCalendar{}
+	if r.Result != nil {
+		c = &r.Result.Calendar
+	}
+	b, err := NewBusinessCalendar(c)
+	if err != nil {
+		return nil, err
+	}
+	return b.ShippingDeadlines(orders, rule, now)
+}
+
+// ShippingDeadlines は orders の注文ごとの発送期限を計算します。結果は orders と同じ順に並びます。
+func (b *BusinessCalendar) ShippingDeadlines(orders []GetOrderOrderModel, rule *ShippingDeadlineRule, now time.Time) ([]ShippingDeadline, error) {
+	list := []ShippingDeadline{}
+	for i := range orders {
+		d, err := b.ShippingDeadline(&orders[i], rule, now)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, *d)
+	}
+	return list, nil
+}
+
+// ShippingDeadline は注文の発送期限を計算します。期限は以下の順に決定します。
+// お届け日指定がある場合は、お届け日から TransitDays 日前以前で最後の発送日です。
+// あす楽希望の場合は、発送日の締め時刻までの注文は当日、それ以外は翌発送日です。
+// それ以外の場合は、商品の納期情報の日数のうち最も長いもの(読み取れない場合は LeadDays)だけ注文日から営業日を進め、その日以降で最初の発送日です。
+// 複数の商品を含む注文は、すべての商品が揃ってから発送するものとして最も遅い商品に合わせます。
+// お届け時間帯の指定は期限に影響しません。
+func (b *BusinessCalendar) ShippingDeadline(o *GetOrderOrderModel, rule *ShippingDeadlineRule, now time.Time) (*ShippingDeadline, error) {
+	r := rule.withDefaults()
+	d := &ShippingDeadline{OrderNumber: o.OrderNumber}
+	ordered := o.OrderDatetime.Time.In(jst)
+	var err error
+	switch {
+	case o.DeliveryDate != nil:
+		d.Reason = SHIPPING_DEADLINE_REASON_DELIVERY_DATE
+		d.Deadline, err = b.previousShippingDay(startOfDay(o.DeliveryDate.Time).AddDate(0, 0, -r.TransitDays))
+	case o.AsurakuFlag == 1:
+		d.Reason = SHIPPING_DEADLINE_REASON_ASURAKU
+		day := startOfDay(ordered)
+		if !b.IsShippingDay(day) || ordered.Sub(day) >= r.AsurakuCutoff {
+			day = day.AddDate(0, 0, 1)
+		}
+		d.Deadline, err = b.NextShippingDay(day)
+	default:
+		days, ok := orderLeadDays(o)
+		d.Reason = SHIPPING_DEADLINE_REASON_DELVDATE_INFO
+		if !ok {
+			days = r.LeadDays
+			d.Reason = SHIPPING_DEADLINE_REASON_DEFAULT
+		}
+		var day time.Time
+		if day, err = b.AddBusinessDays(ordered, days); err == nil {
+			d.Deadline, err = b.NextShippingDay(day)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d.Shipped = o.OrderProgress >= 500 || o.ShippingCompleteReportDatetime != nil
+	if d.Shipped {
+		return d, nil
+	}
+	today := startOfDay(now)
+	d.Overdue = today.After(d.Deadline)
+	riskFrom := d.Deadline
+	for i := 0; i < r.AtRiskShippingDays; i++ {
+		if riskFrom, err = b.previousShippingDay(riskFrom.AddDate(0, 0, -1)); err != nil {
+			return nil, err
+		}
+	}
+	d.AtRisk = !today.Before(riskFrom)
+	return d, nil
+}
+
+// withDefaults は0の項目を既定値に置き換えた設定を返却します。rule が nil の場合はすべて既定値です。
+func (rule *ShippingDeadlineRule) withDefaults() ShippingDeadlineRule {
+	r := ShippingDeadlineRule{}
+	if rule != nil {
+		r = *rule
+	}
+	if r.AsurakuCutoff <= 0 {
+		r.AsurakuCutoff = SHIPPING_DEADLINE_DEFAULT_ASURAKU_CUTOFF
+	}
+	if r.TransitDays <= 0 {
+		r.TransitDays = SHIPPING_DEADLINE_DEFAULT_TRANSIT_DAYS
+	}
+	if r.LeadDays <= 0 {
+		r.LeadDays = SHIPPING_DEADLINE_DEFAULT_LEAD_DAYS
+	}
+	return r
+}
+
+// previousShippingDay は t の日付以前で最後に発送業務を行う日を返却します。
+func (b *BusinessCalendar) previousShippingDay(t time.Time) (time.Time, error) {
+	d := startOfDay(t)
+	for i := 0; i < CALENDAR_SEARCH_MAX_DAYS; i++ {
+		if b.IsShippingDay(d) {
+			return d, nil
+		}
+		d = d.AddDate(0, 0, -1)
+	}
+	return time.Time{}, ErrNoBusinessDay
+}
+
+// orderLeadDays は注文に含まれる商品の納期情報から、発送までの日数のうち最も長いものを返却します。読み取れる納期情報がない場合は false を返却します。
+func orderLeadDays(o *GetOrderOrderModel) (int, bool) {
+	days, found := 0, false
+	for _, p := range o.PackageModelList {
+		for _, item := range p.ItemModelList {
+			if item.DelvdateInfo == nil {
+				continue
+			}
+			n, ok := parseDelvdateInfo(*item.DelvdateInfo)
+			if ok && (!found || n > days) {
+				days, found = n, true
+			}
+		}
+	}
+	return days, found
+}
+
+// parseDelvdateInfo は「1～2日以内に発送予定」「3営業日以内に発送」のような納期情報から、発送までの日数の上限を読み取ります。「当日発送」の場合は0です。
+func parseDelvdateInfo(s string) (int, bool) {
+	s = fullWidthDigits.Replace(s)
+	if m := delvdateInfoPattern.FindStringSubmatch(s); m != nil {
+		n, err := strconv.Atoi(m[1])
+		return n, err == nil
+	}
+	if strings.Contains(s, "当日") {
+		return 0, true
+	}
+	return 0, false
+}
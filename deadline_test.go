@@ -0,0 +1,71 @@
+package rms
+
+import (
+	"testing"
+	"time"
+)
+
+func TestShippingDeadline_期限の計算(t *testing.T) {
+	b := newTestBusinessCalendar(t)
+	delvdate := "１～２日以内に発送予定（店舗休業日を除く）"
+	delvdateLong := "３営業日以内に発送"
+	delivery := JsonDate{time.Date(2020, 5, 8, 0, 0, 0, 0, jst)}
+	cases := []struct {
+		name     string
+		order    GetOrderOrderModel
+		deadline time.Time
+		reason   ShippingDeadlineReason
+	}{
+		{"あす楽締め時刻前", GetOrderOrderModel{AsurakuFlag: 1, OrderDatetime: JsonTime{time.Date(2020, 5, 1, 11, 0, 0, 0, jst)}}, time.Date(2020, 5, 1, 0, 0, 0, 0, jst), SHIPPING_DEADLINE_REASON_ASURAKU},
+		{"あす楽締め時刻後", GetOrderOrderModel{AsurakuFlag: 1, OrderDatetime: JsonTime{time.Date(2020, 5, 1, 13, 0, 0, 0, jst)}}, time.Date(2020, 5, 3, 0, 0, 0, 0, jst), SHIPPING_DEADLINE_REASON_ASURAKU},
+		{"お届け日指定", GetOrderOrderModel{AsurakuFlag: 1, DeliveryDate: &delivery, OrderDatetime: JsonTime{time.Date(2020, 5, 1, 9, 0, 0, 0, jst)}}, time.Date(2020, 5, 7, 0, 0, 0, 0, jst), SHIPPING_DEADLINE_REASON_DELIVERY_DATE},
+		{"納期情報", GetOrderOrderModel{OrderDatetime: JsonTime{time.Date(2020, 5, 1, 9, 0, 0, 0, jst)}, PackageModelList: []GetOrderPackageModel{{ItemModelList: []GetOrderItemModel{{DelvdateInfo: &delvdate}}}}}, time.Date(2020, 5, 3, 0, 0, 0, 0, jst), SHIPPING_DEADLINE_REASON_DELVDATE_INFO},
+		{"納期情報の異なる商品", GetOrderOrderModel{OrderDatetime: JsonTime{time.Date(2020, 5, 1, 9, 0, 0, 0, jst)}, PackageModelList: []GetOrderPackageModel{{ItemModelList: []GetOrderItemModel{{DelvdateInfo: &delvdate}, {DelvdateInfo: &delvdateLong}}}}}, time.Date(2020, 5, 6, 0, 0, 0, 0, jst), SHIPPING_DEADLINE_REASON_DELVDATE_INFO},
+		{"既定の日数", GetOrderOrderModel{OrderDatetime: JsonTime{time.Date(2020, 5, 1, 9, 0, 0, 0, jst)}}, time.Date(2020, 5, 6, 0, 0, 0, 0, jst), SHIPPING_DEADLINE_REASON_DEFAULT},
+	}
+	for _, c := range cases {
+		d, err := b.ShippingDeadline(&c.order, nil, time.Date(2020, 4, 30, 0, 0, 0, 0, jst))
+		if err != nil {
+			t.Errorf("%s: Happend undefined error: %v", c.name, err)
+			continue
+		}
+		if !d.Deadline.Equal(c.deadline) || d.Reason != c.reason {
+			t.Errorf("%s: expected: %v %v, actual: %v %v", c.name, c.deadline, c.reason, d.Deadline, d.Reason)
+		}
+	}
+}
+
+func TestShippingDeadline_期限切れの判定(t *testing.T) {
+	b := newTestBusinessCalendar(t)
+	orders := []GetOrderOrderModel{
+		{OrderNumber: "1", OrderProgress: 300, AsurakuFlag: 1, OrderDatetime: JsonTime{time.Date(2020, 5, 1, 13, 0, 0, 0, jst)}},
+		{OrderNumber: "2", OrderProgress: 500, AsurakuFlag: 1, OrderDatetime: JsonTime{time.Date(2020, 5, 1, 13, 0, 0, 0, jst)}},
+	}
+	rule := &ShippingDeadlineRule{AtRiskShippingDays: 1}
+
+	list, err := b.ShippingDeadlines(orders, rule, time.Date(2020, 5, 1, 18, 0, 0, 0, jst))
+	if err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	if !list[0].AtRisk || list[0].Overdue || list[1].AtRisk || !list[1].Shipped {
+		t.Errorf("expected: only 1 at risk, actual: %+v", list)
+	}
+
+	list, _ = b.ShippingDeadlines(orders, rule, time.Date(2020, 5, 4, 9, 0, 0, 0, jst))
+	if !list[0].Overdue || !list[0].AtRisk {
+		t.Errorf("expected: overdue, actual: %+v", list[0])
+	}
+}
+
+func TestParseDelvdateInfo_納期情報の解析(t *testing.T) {
+	cases := map[string]int{"3～5日以内に発送予定": 5, "当日発送": 0, "7日以内に発送": 7, "3営業日以内に発送": 3, "２～４営業日以内に発送予定（店舗休業日を除く）": 4}
+	for s, expected := range cases {
+		if n, ok := parseDelvdateInfo(s); !ok || n != expected {
+			t.Errorf("%s expected: %d, actual: %d", s, expected, n)
+		}
+	}
+	if _, ok := parseDelvdateInfo("お取り寄せ"); ok {
+		t.Error("expected: false, actual: true")
+	}
+}
@@ -0,0 +1,190 @@
+package rms
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	// ErrNotInitialized はRMSApiが初期化されていない状態でAPIを呼び出した場合のエラーです。
+	ErrNotInitialized = errors.New("Uninitialized")
+
+	// ErrUnauthorized はサービスシークレット、ライセンスキーによる認証に失敗した場合のエラーです。APIError から errors.Is で判定できます。
+	ErrUnauthorized = errors.New("Unauthorized")
+)
+
+type (
+	// APIError はRMS WEB SERVICEがエラーを返却した場合のエラーです。errors.As で取り出すことができます。
+	APIError struct {
+		// StatusCode はHTTPのステータスコードです。
+		StatusCode int
+
+		// MessageType はエラーの原因となったメッセージのメッセージ種別です。
+		MessageType string
+
+		// MessageCode はエラーの原因となったメッセージのメッセージコードです。
+		MessageCode string
+
+		// Message はエラーの原因となったメッセージです。
+		Message string
+
+		// MessageModelList はレスポンスに含まれていたすべてのメッセージです。
+		MessageModelList []CommonMessageModelResponse
+
+		// OrderErrors は注文番号が特定できるエラーの一覧です。
+		OrderErrors []*OrderError
+	}
+
+	// OrderError は注文ごとのエラーです。errors.As で取り出すことができます。
+	OrderError struct {
+		// CommonMessageModelResponse はエラー情報が含まれます。
+		CommonMessageModelResponse
+
+		// OrderNumber は注文番号です。
+		OrderNumber string
+	}
+
+	// ValidationError はリクエストを送信する前に検出した、RMSの制約を満たさない入力値のエラーです。
+	ValidationError struct {
+		// Field は問題のある項目名です。
+		Field string
+
+		// Message はエラーの内容です。
+		Message string
+	}
+
+	// errorsV2 は商品API 2.0 等でエラーの場合に返却されるレスポンスです。
+	errorsV2 struct {
+		Errors []errorV2 `json:"errors"`
+	}
+
+	// errorV2 は errorsV2 に含まれるエラーです。PropertyPath は inventories[0].quantity のような、エラーとなった項目のパスです。
+	errorV2 struct {
+		Code     string `json:"code"`
+		Message  string `json:"message"`
+		Metadata struct {
+			PropertyPath string `json:"propertyPath"`
+		} `json:"metadata"`
+	}
+
+	// errorResults は楽天ペイ受注APIで認証エラー等の場合に返却されるレスポンスです。
+	errorResults struct {
+		Results struct {
+			ErrorCode string `json:"errorCode"`
+			Message   string `json:"message"`
+		} `json:"Results"`
+	}
+)
+
+// Error はエラーの内容を文字列で返却します。
+func (e *APIError) Error() string {
+	if e.MessageCode == "" && e.Message == "" {
+		return fmt.Sprintf("rms: status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("rms: %s %s (status %d)", e.MessageCode, e.Message, e.StatusCode)
+}
+
+// Is は target が *APIError の場合、メッセージコードとステータスコードのうち指定されたものが一致するかを判定します。
+// errors.Is(err, &APIError{MessageCode: "ES04-01"}) のように使用します。
+func (e *APIError) Is(target error) bool {
+	t, ok := target.(*APIError)
+	if !ok {
+		return false
+	}
+	if t.MessageCode != "" && t.MessageCode != e.MessageCode {
+		return false
+	}
+	if t.StatusCode != 0 && t.StatusCode != e.StatusCode {
+		return false
+	}
+	return t.MessageCode != "" || t.StatusCode != 0
+}
+
+// Unwrap は認証エラーの場合 ErrUnauthorized を、注文ごとのエラーがある場合はそれぞれの OrderError を返却します。
+func (e *APIError) Unwrap() []error {
+	var errs []error
+	if e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden {
+		errs = append(errs, ErrUnauthorized)
+	}
+	for _, oe := range e.OrderErrors {
+		errs = append(errs, oe)
+	}
+	return errs
+}
+
+// Error はエラーの内容を文字列で返却します。
+func (e *OrderError) Error() string {
+	return fmt.Sprintf("rms: order %s: %s %s", e.OrderNumber, e.MessageCode, e.Message)
+}
+
+// Is は target が *OrderError の場合、注文番号とメッセージコードのうち指定されたものが一致するかを判定します。
+func (e *OrderError) Is(target error) bool {
+	t, ok := target.(*OrderError)
+	if !ok {
+		return false
+	}
+	if t.OrderNumber != "" && t.OrderNumber != e.OrderNumber {
+		return false
+	}
+	if t.MessageCode != "" && t.MessageCode != e.MessageCode {
+		return false
+	}
+	return t.OrderNumber != "" || t.MessageCode != ""
+}
+
+// Error はエラーの内容を文字列で返却します。
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("rms: invalid %s: %s", e.Field, e.Message)
+}
+
+// newAPIError はレスポンスから APIError を生成します。メッセージのうち最初のINFO以外のものをエラーの原因とします。
+func newAPIError(status int, body []byte, list []CommonMessageModelResponse) *APIError {
+	e := &APIError{StatusCode: status, MessageModelList: list}
+	for _, m := range list {
+		if m.MessageType != "INFO" {
+			e.MessageType = m.MessageType
+			e.MessageCode = m.MessageCode
+			e.Message = m.Message
+			return e
+		}
+	}
+	if len(list) > 0 {
+		e.MessageType = list[0].MessageType
+		e.MessageCode = list[0].MessageCode
+		e.Message = list[0].Message
+		return e
+	}
+	r := errorResults{}
+	if json.Unmarshal(body, &r) == nil && r.Results.ErrorCode != "" {
+		e.MessageType = "ERROR"
+		e.MessageCode = r.Results.ErrorCode
+		e.Message = r.Results.Message
+	}
+	return e
+}
+
+// newAPIErrorV2 は商品API 2.0 等のエラーのレスポンスから APIError を生成します。
+func newAPIErrorV2(status int, body []byte) *APIError {
+	r := errorsV2{}
+	json.Unmarshal(body, &r)
+	list := []CommonMessageModelResponse{}
+	for _, e := range r.Errors {
+		list = append(list, e.messageModel())
+	}
+	return newAPIError(status, body, list)
+}
+
+// messageModel はエラーを楽天ペイ受注APIと同じ形式に変換します。
+func (e errorV2) messageModel() CommonMessageModelResponse {
+	m := e.Message
+	if e.Metadata.PropertyPath != "" {
+		m = fmt.Sprintf("%s: %s", e.Metadata.PropertyPath, e.Message)
+	}
+	return CommonMessageModelResponse{MessageType: "ERROR", MessageCode: e.Code, Message: m}
+}
+
+func isSuccessStatus(status int) bool {
+	return status >= 200 && status < 300
+}
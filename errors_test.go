@@ -0,0 +1,55 @@
+package rms
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIError_認証失敗(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"Results":{"errorCode":"ES01-01","message":"Unauthorized"}}`))
+	}))
+	defer ts.Close()
+
+	a := NewRMSApi("hoge", "fuga", WithBaseURL(ts.URL))
+	_, err := a.GetOrder([]string{"000000-20200101-0000000000"}, 3)
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expected: %v, actual: %v", ErrUnauthorized, err)
+	}
+	apiErr := &APIError{}
+	if !errors.As(err, &apiErr) {
+		t.Errorf("expected: *APIError, actual: %T", err)
+		t.FailNow()
+	}
+	if apiErr.StatusCode != http.StatusUnauthorized || apiErr.MessageCode != "ES01-01" {
+		t.Errorf("expected: 401 ES01-01, actual: %d %s", apiErr.StatusCode, apiErr.MessageCode)
+	}
+}
+
+func TestAPIError_注文ごとのエラー(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"MessageModelList":[{"messageType":"ERROR","messageCode":"ORDER_EXT_API_UPDATE_ORDERMEMO_ERROR_004","message":"注文番号が存在しません。","orderNumber":"000000-20200101-0000000000"}]}`))
+	}))
+	defer ts.Close()
+
+	a := NewRMSApi("hoge", "fuga", WithBaseURL(ts.URL))
+	err := a.UpdateOrderMemo(&UpdateOrderMemoCondition{OrderNumber: "000000-20200101-0000000000"})
+	if !errors.Is(err, &APIError{MessageCode: "ORDER_EXT_API_UPDATE_ORDERMEMO_ERROR_004"}) {
+		t.Errorf("expected: ORDER_EXT_API_UPDATE_ORDERMEMO_ERROR_004, actual: %v", err)
+	}
+	if errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expected: not %v, actual: %v", ErrUnauthorized, err)
+	}
+	orderErr := &OrderError{}
+	if !errors.As(err, &orderErr) {
+		t.Errorf("expected: *OrderError, actual: %T", err)
+		t.FailNow()
+	}
+	if orderErr.OrderNumber != "000000-20200101-0000000000" {
+		t.Errorf("expected: 000000-20200101-0000000000, actual: %s", orderErr.OrderNumber)
+	}
+}
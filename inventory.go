@@ -0,0 +1,232 @@
+package rms
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+const (
+	// RMS WEB SERVICEの在庫APIの在庫情報の一括取得用のエンドポイントです。
+	INVENTORIES_BULK_GET_URL = "https://api.rms.rakuten.co.jp/es/2.0/inventories/bulk-get"
+
+	// RMS WEB SERVICEの在庫APIの在庫情報の一括更新用のエンドポイントです。
+	INVENTORIES_BULK_UPSERT_URL = "https://api.rms.rakuten.co.jp/es/2.0/inventories/bulk-upsert"
+
+	// INVENTORIES_BULK_GET_MAX は在庫情報の一括取得で一度に指定できるSKUの数です。
+	INVENTORIES_BULK_GET_MAX = 1000
+
+	// INVENTORIES_BULK_UPSERT_MAX は在庫情報の一括更新で一度に指定できるSKUの数です。
+	INVENTORIES_BULK_UPSERT_MAX = 400
+
+	// INVENTORY_MAX_QUANTITY は在庫数に指定できる最大値です。
+	INVENTORY_MAX_QUANTITY = 99999
+)
+
+// InventoryUpdateMode は在庫数の更新方法を表します。
+type InventoryUpdateMode string
+
+const (
+	// INVENTORY_UPDATE_MODE_ABSOLUTE は在庫数を指定した値で置き換えます。
+	INVENTORY_UPDATE_MODE_ABSOLUTE InventoryUpdateMode = "ABSOLUTE"
+
+	// INVENTORY_UPDATE_MODE_ADD は在庫数に指定した値を加算します。
+	INVENTORY_UPDATE_MODE_ADD InventoryUpdateMode = "ADD"
+
+	// INVENTORY_UPDATE_MODE_SUBTRACT は在庫数から指定した値を減算します。
+	INVENTORY_UPDATE_MODE_SUBTRACT InventoryUpdateMode = "SUBTRACT"
+)
+
+type (
+	// InventoryKey は在庫を管理する単位であるSKUを特定するためのキーです。
+	// 通常在庫の商品はSKUを1件だけ持つ商品として、項目選択肢別在庫の商品は選択肢の組み合わせごとにSKUを持つ商品として扱います。
+	InventoryKey struct {
+		// ManageNumber は商品管理番号です。
+		ManageNumber string `json:"manageNumber"`
+
+		// VariantID はSKU管理番号です。
+		VariantID string `json:"variantId"`
+	}
+
+	// InventoryModel は在庫APIで得られるSKUごとの在庫情報です。
+	InventoryModel struct {
+		InventoryKey
+
+		// Quantity は在庫数です。
+		Quantity int `json:"quantity"`
+
+		// Created は登録日時です。
+		Created string `json:"created"`
+
+		// Updated は更新日時です。
+		Updated string `json:"updated"`
+	}
+
+	// InventoryBulkGetRequest は在庫APIの在庫情報の一括取得のリクエストです。
+	InventoryBulkGetRequest struct {
+		// Inventories は取得するSKUです。
+		Inventories []InventoryKey `json:"inventories"`
+	}
+
+	// InventoryBulkGetResponse は在庫APIの在庫情報の一括取得で得られるレスポンスです。
+	InventoryBulkGetResponse struct {
+		// Inventories はSKUごとの在庫情報です。存在しないSKUは含まれません。
+		Inventories []InventoryModel `json:"inventories"`
+	}
+
+	// InventoryUpdateCondition は在庫APIのSKUごとの在庫数の更新内容です。
+	InventoryUpdateCondition struct {
+		InventoryKey
+
+		// Mode は更新方法です。
+		Mode InventoryUpdateMode `json:"mode"`
+
+		// Quantity は在庫数です。Mode が INVENTORY_UPDATE_MODE_ADD、INVENTORY_UPDATE_MODE_SUBTRACT の場合は増減する数です。0~99999まで指定することができます。
+		Quantity int `json:"quantity"`
+	}
+
+	// InventoryBulkUpsertRequest は在庫APIの在庫情報の一括更新のリクエストです。
+	InventoryBulkUpsertRequest struct {
+		// Inventories は更新するSKUの更新内容です。
+		Inventories []InventoryUpdateCondition `json:"inventories"`
+	}
+
+	// InventoryUpdateResult は UpdateInventories で得られるSKUごとの更新結果です。
+	InventoryUpdateResult struct {
+		InventoryUpdateCondition
+
+		// Err は更新できなかった場合のエラーです。更新できた場合は nil です。
+		// 送信前に検出した入力値のエラーは ValidationError、RMSが返却したエラーは APIError です。同じリクエストに含まれる他のSKUのエラーによって更新されなかった場合も APIError になります。
+		Err error
+	}
+)
+
+// GetInventories は在庫APIでSKUごとの在庫数を取得します。keys は取得するSKUで、1000件を超える場合は1000件ずつに分割して取得します。
+func (a *RMSApi) GetInventories(keys []InventoryKey) (*InventoryBulkGetResponse, error) {
+	return a.GetInventoriesContext(context.Background(), keys)
+}
+
+// GetInventoriesContext は GetInventories にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) GetInventoriesContext(ctx context.Context, keys []InventoryKey) (*InventoryBulkGetResponse, error) {
+	if !a.initialized() {
+		return nil, ErrNotInitialized
+	}
+	result := InventoryBulkGetResponse{Inventories: []InventoryModel{}}
+	for start := 0; start < len(keys); start += INVENTORIES_BULK_GET_MAX {
+		end := start + INVENTORIES_BULK_GET_MAX
+		if end > len(keys) {
+			end = len(keys)
+		}
+		r := InventoryBulkGetResponse{}
+		if err := a.sendJSON(ctx, "POST", INVENTORIES_BULK_GET_URL, nil, InventoryBulkGetRequest{keys[start:end]}, &r); err != nil {
+			return nil, err
+		}
+		result.Inventories = append(result.Inventories, r.Inventories...)
+	}
+	return &result, nil
+}
+
+// UpdateInventories は在庫APIでSKUごとの在庫数を一括で更新します。conds は更新内容で、400件を超える場合は400件ずつに分割して送信します。
+// SKUごとの結果は conds と同じ順に返却されます。通信エラーや認証エラー等で処理を継続できない場合は、その時点で未送信のSKUを含む残りのSKUの Err にそのエラーを設定し、結果とともにエラーを返却します。
+func (a *RMSApi) UpdateInventories(conds []InventoryUpdateCondition) ([]InventoryUpdateResult, error) {
+	return a.UpdateInventoriesContext(context.Background(), conds)
+}
+
+// UpdateInventoriesContext は UpdateInventories にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) UpdateInventoriesContext(ctx context.Context, conds []InventoryUpdateCondition) ([]InventoryUpdateResult, error) {
+	if !a.initialized() {
+		return nil, ErrNotInitialized
+	}
+	results := make([]InventoryUpdateResult, len(conds))
+	pending := []int{}
+	for i, c := range conds {
+		results[i].InventoryUpdateCondition = c
+		if err := c.validate(); err != nil {
+			results[i].Err = err
+			continue
+		}
+		pending = append(pending, i)
+	}
+
+	for start := 0; start < len(pending); start += INVENTORIES_BULK_UPSERT_MAX {
+		end := start + INVENTORIES_BULK_UPSERT_MAX
+		if end > len(pending) {
+			end = len(pending)
+		}
+		idx := pending[start:end]
+		req := InventoryBulkUpsertRequest{Inventories: []InventoryUpdateCondition{}}
+		for _, i := range idx {
+			req.Inventories = append(req.Inventories, conds[i])
+		}
+		status, byteArray, err := a.postJSON(ctx, INVENTORIES_BULK_UPSERT_URL, req)
+		if err != nil {
+			return abortInventoryUpdate(results, pending[start:], err)
+		}
+		if isSuccessStatus(status) {
+			continue
+		}
+
+		batchErr := newAPIErrorV2(status, byteArray)
+		if errors.Is(batchErr, ErrUnauthorized) {
+			return abortInventoryUpdate(results, pending[start:], batchErr)
+		}
+		for _, i := range idx {
+			results[i].Err = batchErr
+		}
+		r := errorsV2{}
+		if json.Unmarshal(byteArray, &r) != nil {
+			continue
+		}
+		for _, e := range r.Errors {
+			n, ok := inventoryIndex(e.Metadata.PropertyPath)
+			if !ok || n >= len(idx) {
+				continue
+			}
+			results[idx[n]].Err = newAPIError(status, byteArray, []CommonMessageModelResponse{e.messageModel()})
+		}
+	}
+	return results, nil
+}
+
+// abortInventoryUpdate は処理を継続できない場合に、rest の位置のSKUの結果に err を設定して返却します。
+func abortInventoryUpdate(results []InventoryUpdateResult, rest []int, err error) ([]InventoryUpdateResult, error) {
+	for _, i := range rest {
+		results[i].Err = err
+	}
+	return results, err
+}
+
+// validate は送信前に更新内容を検証します。
+func (c *InventoryUpdateCondition) validate() error {
+	if c.ManageNumber == "" || c.VariantID == "" {
+		return &ValidationError{Field: "manageNumber", Message: "商品管理番号とSKU管理番号を指定してください。"}
+	}
+	switch c.Mode {
+	case INVENTORY_UPDATE_MODE_ABSOLUTE, INVENTORY_UPDATE_MODE_ADD, INVENTORY_UPDATE_MODE_SUBTRACT:
+	default:
+		return &ValidationError{Field: "mode", Message: "更新方法が不正です。"}
+	}
+	if c.Quantity < 0 || c.Quantity > INVENTORY_MAX_QUANTITY {
+		return &ValidationError{Field: "quantity", Message: "在庫数は0以上99999以下でなければいけません。"}
+	}
+	return nil
+}
+
+// inventoryIndex は inventories[3].quantity のようなエラーの項目のパスから、リクエスト中の位置を取得します。
+func inventoryIndex(path string) (int, bool) {
+	if !strings.HasPrefix(path, "inventories[") {
+		return 0, false
+	}
+	path = strings.TrimPrefix(path, "inventories[")
+	end := strings.Index(path, "]")
+	if end < 0 {
+		return 0, false
+	}
+	n, err := strconv.Atoi(path[:end])
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
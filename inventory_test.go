@@ -0,0 +1,110 @@
+package rms
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetInventories_在庫数の取得(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := InventoryBulkGetRequest{}
+		json.NewDecoder(r.Body).Decode(&req)
+		res := InventoryBulkGetResponse{}
+		for _, k := range req.Inventories {
+			res.Inventories = append(res.Inventories, InventoryModel{InventoryKey: k, Quantity: 10})
+		}
+		json.NewEncoder(w).Encode(res)
+	}))
+	defer ts.Close()
+
+	a := NewRMSApi("hoge", "fuga", WithBaseURL(ts.URL))
+	r, err := a.GetInventories([]InventoryKey{{"hoge-001", "sku-red"}, {"hoge-001", "sku-blue"}})
+	if err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	if len(r.Inventories) != 2 || r.Inventories[1].VariantID != "sku-blue" || r.Inventories[1].Quantity != 10 {
+		t.Errorf("expected: 2 inventories, actual: %v", r.Inventories)
+	}
+}
+
+func TestUpdateInventories_SKUごとの結果(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		req := InventoryBulkUpsertRequest{}
+		json.NewDecoder(r.Body).Decode(&req)
+		if len(req.Inventories) != 2 || req.Inventories[1].Mode != INVENTORY_UPDATE_MODE_SUBTRACT {
+			t.Errorf("expected: 2 inventories, actual: %v", req.Inventories)
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"errors":[{"code":"IE0301","message":"The variant does not exist.","metadata":{"propertyPath":"inventories[1].variantId"}}]}`))
+	}))
+	defer ts.Close()
+
+	a := NewRMSApi("hoge", "fuga", WithBaseURL(ts.URL))
+	results, err := a.UpdateInventories([]InventoryUpdateCondition{
+		{InventoryKey{"hoge-001", "sku-red"}, INVENTORY_UPDATE_MODE_ABSOLUTE, 5},
+		{InventoryKey{"hoge-001", "sku-blue"}, INVENTORY_UPDATE_MODE_ADD, 100000},
+		{InventoryKey{"hoge-001", "sku-green"}, INVENTORY_UPDATE_MODE_SUBTRACT, 1},
+	})
+	if err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	if requests != 1 || len(results) != 3 {
+		t.Errorf("expected: 1 request and 3 results, actual: %d %d", requests, len(results))
+		t.FailNow()
+	}
+	ve := &ValidationError{}
+	if !errors.As(results[1].Err, &ve) || ve.Field != "quantity" {
+		t.Errorf("expected: *ValidationError, actual: %v", results[1].Err)
+	}
+	if !errors.Is(results[2].Err, &APIError{MessageCode: "IE0301"}) {
+		t.Errorf("expected: IE0301, actual: %v", results[2].Err)
+	}
+	apiErr := &APIError{}
+	if !errors.As(results[0].Err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected: *APIError, actual: %v", results[0].Err)
+	}
+}
+
+func TestUpdateInventories_途中で失敗(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests > 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"errors":[{"code":"ES01-01","message":"Unauthorized"}]}`))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	conds := []InventoryUpdateCondition{}
+	for i := 0; i < INVENTORIES_BULK_UPSERT_MAX*2+1; i++ {
+		conds = append(conds, InventoryUpdateCondition{InventoryKey{"hoge-001", fmt.Sprintf("sku-%d", i)}, INVENTORY_UPDATE_MODE_ABSOLUTE, 1})
+	}
+	a := NewRMSApi("hoge", "fuga", WithBaseURL(ts.URL))
+	results, err := a.UpdateInventories(conds)
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expected: %v, actual: %v", ErrUnauthorized, err)
+	}
+	if requests != 2 || len(results) != len(conds) {
+		t.Errorf("expected: 2 requests and %d results, actual: %d %d", len(conds), requests, len(results))
+		t.FailNow()
+	}
+	if results[INVENTORIES_BULK_UPSERT_MAX-1].Err != nil {
+		t.Errorf("expected: nil, actual: %v", results[INVENTORIES_BULK_UPSERT_MAX-1].Err)
+	}
+	for _, i := range []int{INVENTORIES_BULK_UPSERT_MAX, len(conds) - 1} {
+		if !errors.Is(results[i].Err, ErrUnauthorized) || results[i].VariantID != conds[i].VariantID {
+			t.Errorf("expected: %v for %s, actual: %v", ErrUnauthorized, conds[i].VariantID, results[i])
+		}
+	}
+}
@@ -0,0 +1,467 @@
+package rms
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+)
+
+const (
+	// RMS WEB SERVICEの商品APIの商品情報取得用のエンドポイントです。
+	ITEM_GET_URL = "https://api.rms.rakuten.co.jp/es/1.0/item/get"
+
+	// RMS WEB SERVICEの商品APIの商品検索用のエンドポイントです。
+	ITEM_SEARCH_URL = "https://api.rms.rakuten.co.jp/es/1.0/item/search"
+
+	// RMS WEB SERVICEの商品APIの商品登録用のエンドポイントです。
+	ITEM_INSERT_URL = "https://api.rms.rakuten.co.jp/es/1.0/item/insert"
+
+	// RMS WEB SERVICEの商品APIの商品更新用のエンドポイントです。
+	ITEM_UPDATE_URL = "https://api.rms.rakuten.co.jp/es/1.0/item/update"
+
+	// RMS WEB SERVICEの商品APIの商品削除用のエンドポイントです。
+	ITEM_DELETE_URL = "https://api.rms.rakuten.co.jp/es/1.0/item/delete"
+
+	// ITEM_SEARCH_MAX_LIMIT は商品検索で一度に取得できる商品の数です。
+	ITEM_SEARCH_MAX_LIMIT = 100
+)
+
+// ItemTaxFlag は商品価格が税込みかどうかを表します。
+type ItemTaxFlag int
+
+const (
+	// ITEM_TAX_FLAG_INCLUDED は税込みです。
+	ITEM_TAX_FLAG_INCLUDED ItemTaxFlag = 0
+
+	// ITEM_TAX_FLAG_EXCLUDED は税別です。
+	ITEM_TAX_FLAG_EXCLUDED ItemTaxFlag = 1
+)
+
+// ItemPostageFlag は商品価格が送料込みかどうかを表します。
+type ItemPostageFlag int
+
+const (
+	// ITEM_POSTAGE_FLAG_EXCLUDED は送料別です。
+	ITEM_POSTAGE_FLAG_EXCLUDED ItemPostageFlag = 0
+
+	// ITEM_POSTAGE_FLAG_INCLUDED は送料込みです。
+	ITEM_POSTAGE_FLAG_INCLUDED ItemPostageFlag = 1
+)
+
+type (
+	// ItemApiStatus は商品APIのレスポンスに含まれる処理状況です。
+	ItemApiStatus struct {
+		// InterfaceID は呼び出したAPIの名前です。item.get 等が入ります。
+		InterfaceID string `xml:"interfaceId"`
+
+		// SystemStatus はシステムの状態です。OK もしくは NG が入ります。
+		SystemStatus string `xml:"systemStatus"`
+
+		// Message はメッセージです。
+		Message string `xml:"message"`
+
+		// RequestID はリクエストIDです。
+		RequestID string `xml:"requestId"`
+	}
+
+	// ItemErrorMessage は商品APIで項目ごとに返却されるエラーメッセージです。
+	ItemErrorMessage struct {
+		// FieldID はエラーとなった項目名です。
+		FieldID string `xml:"fieldId"`
+
+		// MsgCode はメッセージコードです。
+		MsgCode string `xml:"msgCode"`
+
+		// Msg はメッセージです。
+		Msg string `xml:"msg"`
+	}
+
+	// ItemResult は商品APIの処理結果です。
+	ItemResult struct {
+		// Code は結果コードです。N000 の場合は正常に処理が行われています。それ以外のコードは ResultMessage を参照してください。
+		Code string `xml:"code"`
+
+		// ErrorMessages は項目ごとのエラーメッセージです。
+		ErrorMessages []ItemErrorMessage `xml:"errorMessages>errorMessage"`
+	}
+
+	// ItemImageModel は商品画像です。
+	ItemImageModel struct {
+		// ImageURL は画像のURLです。R-Cabinetに登録された画像のURLを指定します。
+		ImageURL string `xml:"imageUrl"`
+
+		// ImageAlt は画像の説明(alt)です。
+		ImageAlt string `xml:"imageAlt,omitempty"`
+	}
+
+	// ItemOptionValueModel は項目選択肢の選択肢です。
+	ItemOptionValueModel struct {
+		// Value は選択肢です。
+		Value string `xml:"value"`
+	}
+
+	// ItemOptionModel は項目選択肢です。
+	ItemOptionModel struct {
+		// OptionName は項目名です。
+		OptionName string `xml:"optionName"`
+
+		// OptionStyle は表示形式です。以下のいずれかを指定することができます。
+		// 0: セレクトボックス
+		// 1: チェックボックス
+		OptionStyle int `xml:"optionStyle"`
+
+		// OptionValues は選択肢の一覧です。
+		OptionValues []ItemOptionValueModel `xml:"optionValues>optionValue"`
+	}
+
+	// ItemInventoryModel は在庫設定です。
+	ItemInventoryModel struct {
+		// InventoryType は在庫タイプです。以下のいずれかを指定することができます。
+		// 0: 在庫設定なし
+		// 1: 通常在庫設定
+		// 2: 項目選択肢別在庫設定
+		InventoryType int `xml:"inventoryType"`
+
+		// InventoryCount は在庫数です。通常在庫設定の場合に使用します。
+		InventoryCount *int `xml:"inventoryCount,omitempty"`
+
+		// HorizontalName は項目選択肢別在庫の横軸の項目名です。
+		HorizontalName string `xml:"horizontalName,omitempty"`
+
+		// VerticalName は項目選択肢別在庫の縦軸の項目名です。
+		VerticalName string `xml:"verticalName,omitempty"`
+	}
+
+	// ItemCategoryModel は商品が所属する店舗内カテゴリです。
+	ItemCategoryModel struct {
+		// CategoryID はカテゴリIDです。
+		CategoryID int `xml:"categoryId"`
+	}
+
+	// ItemModel は商品APIで取り扱う商品です。更新の場合は、値を指定した項目のみ更新されます。
+	ItemModel struct {
+		// ItemURL は商品管理番号です。商品ページのURLに使用されます。
+		ItemURL string `xml:"itemUrl"`
+
+		// ItemNumber は商品番号です。
+		ItemNumber string `xml:"itemNumber,omitempty"`
+
+		// ItemName は商品名です。全角127文字以内でなければいけません。
+		ItemName string `xml:"itemName,omitempty"`
+
+		// ItemPrice は販売価格です。
+		ItemPrice int `xml:"itemPrice,omitempty"`
+
+		// GenreID は全商品ディレクトリIDです。
+		GenreID string `xml:"genreId,omitempty"`
+
+		// CatalogID はカタログID(JANコード等)です。
+		CatalogID string `xml:"catalogId,omitempty"`
+
+		// CatchCopyForPC はPC用キャッチコピーです。
+		CatchCopyForPC string `xml:"catchCopyForPC,omitempty"`
+
+		// CatchCopyForMobile はモバイル用キャッチコピーです。
+		CatchCopyForMobile string `xml:"catchCopyForMobile,omitempty"`
+
+		// DescriptionForPC はPC用商品説明文です。HTMLを使用することができます。
+		DescriptionForPC string `xml:"descriptionForPC,omitempty"`
+
+		// DescriptionForMobile はモバイル用商品説明文です。
+		DescriptionForMobile string `xml:"descriptionForMobile,omitempty"`
+
+		// DescriptionForSmartPhone はスマートフォン用商品説明文です。HTMLを使用することができます。
+		DescriptionForSmartPhone string `xml:"descriptionForSmartPhone,omitempty"`
+
+		// DescriptionBySalesMethod はPC用販売説明文です。HTMLを使用することができます。
+		DescriptionBySalesMethod string `xml:"descriptionBySalesMethod,omitempty"`
+
+		// TaxFlag は消費税です。
+		TaxFlag *ItemTaxFlag `xml:"taxFlag,omitempty"`
+
+		// TaxRate は消費税率です。0.1 のように指定します。
+		TaxRate string `xml:"taxRate,omitempty"`
+
+		// PostageFlag は送料です。
+		PostageFlag *ItemPostageFlag `xml:"postageFlag,omitempty"`
+
+		// DaibikiryoFlag は代引料です。以下のいずれかを指定することができます。
+		// 0: 代引料別
+		// 1: 代引料込み
+		DaibikiryoFlag *int `xml:"daibikiryoFlag,omitempty"`
+
+		// DepotFlag は倉庫指定です。以下のいずれかを指定することができます。
+		// 0: 販売中
+		// 1: 倉庫に入れる
+		DepotFlag *int `xml:"depotFlag,omitempty"`
+
+		// Images は商品画像です。最大20枚まで指定することができます。
+		Images []ItemImageModel `xml:"images>image,omitempty"`
+
+		// Options は項目選択肢です。
+		Options []ItemOptionModel `xml:"options>option,omitempty"`
+
+		// ItemInventory は在庫設定です。
+		ItemInventory *ItemInventoryModel `xml:"itemInventory,omitempty"`
+
+		// Categories は商品が所属する店舗内カテゴリです。更新の場合は、指定したカテゴリで置き換えます。
+		Categories []ItemCategoryModel `xml:"categories>categoryInfo,omitempty"`
+	}
+
+	// ItemSearchCondition は商品APIの商品検索の条件です。指定しない項目は条件に含まれません。
+	ItemSearchCondition struct {
+		// ItemName は商品名です。部分一致で検索します。
+		ItemName string
+
+		// ItemNumber は商品番号です。
+		ItemNumber string
+
+		// CatchCopy はキャッチコピーです。部分一致で検索します。
+		CatchCopy string
+
+		// GenreID は全商品ディレクトリIDです。
+		GenreID string
+
+		// ItemPriceFrom は販売価格の下限です。
+		ItemPriceFrom *int
+
+		// ItemPriceTo は販売価格の上限です。
+		ItemPriceTo *int
+
+		// DepotFlag は倉庫指定です。
+		DepotFlag *int
+
+		// Offset は取得を開始する位置です。
+		Offset int
+
+		// Limit は取得する商品の数です。1~100まで指定することができます。それ以外の場合は100件取得します。
+		Limit int
+	}
+
+	// ItemDeleteCondition は商品APIの商品削除の条件です。
+	ItemDeleteCondition struct {
+		// ItemURL は商品管理番号です。
+		ItemURL string `xml:"itemUrl"`
+	}
+
+	// ItemGetResult は商品APIの商品情報取得の処理結果です。
+	ItemGetResult struct {
+		ItemResult
+
+		// Item は商品です。
+		Item *ItemModel `xml:"item"`
+	}
+
+	// ItemGetResponse は商品APIの商品情報取得で得られるレスポンスです。
+	ItemGetResponse struct {
+		// Status は処理状況です。
+		Status ItemApiStatus `xml:"status"`
+
+		// Result は処理結果です。
+		Result ItemGetResult `xml:"itemGetResult"`
+	}
+
+	// ItemSearchResult は商品APIの商品検索の処理結果です。
+	ItemSearchResult struct {
+		ItemResult
+
+		// NumFound は条件に一致した商品の数です。
+		NumFound int `xml:"numFound"`
+
+		// Items は商品です。
+		Items []ItemModel `xml:"items>item"`
+	}
+
+	// ItemSearchResponse は商品APIの商品検索で得られるレスポンスです。
+	ItemSearchResponse struct {
+		// Status は処理状況です。
+		Status ItemApiStatus `xml:"status"`
+
+		// Result は処理結果です。
+		Result ItemSearchResult `xml:"itemSearchResult"`
+	}
+
+	// ItemUpdateResponse は商品APIの商品登録・更新・削除で得られるレスポンスです。
+	ItemUpdateResponse struct {
+		// Status は処理状況です。
+		Status ItemApiStatus `xml:"status"`
+
+		// InsertResult は商品登録の処理結果です。
+		InsertResult *ItemResult `xml:"itemInsertResult"`
+
+		// UpdateResult は商品更新の処理結果です。
+		UpdateResult *ItemResult `xml:"itemUpdateResult"`
+
+		// DeleteResult は商品削除の処理結果です。
+		DeleteResult *ItemResult `xml:"itemDeleteResult"`
+	}
+
+	// ItemRequest は商品APIの商品登録・更新・削除のリクエストです。
+	ItemRequest struct {
+		XMLName xml.Name `xml:"request"`
+
+		// Insert は登録する商品です。
+		Insert *ItemModel `xml:"itemInsertRequest>item,omitempty"`
+
+		// Update は更新する商品です。指定した項目のみ更新されます。
+		Update *ItemModel `xml:"itemUpdateRequest>item,omitempty"`
+
+		// Delete は削除する商品です。
+		Delete *ItemDeleteCondition `xml:"itemDeleteRequest>item,omitempty"`
+	}
+)
+
+// GetItem は商品APIで商品情報を取得します。itemURL は商品管理番号です。
+func (a *RMSApi) GetItem(itemURL string) (*ItemGetResponse, error) {
+	return a.GetItemContext(context.Background(), itemURL)
+}
+
+// GetItemContext は GetItem にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) GetItemContext(ctx context.Context, itemURL string) (*ItemGetResponse, error) {
+	if !a.initialized() {
+		return nil, ErrNotInitialized
+	}
+	params := url.Values{}
+	params.Add("itemUrl", itemURL)
+	result := ItemGetResponse{}
+	if err := a.sendItemXML(ctx, "GET", ITEM_GET_URL, params, nil, &result, &result.Result.ItemResult); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// SearchItem は商品APIで商品を検索します。cond は検索条件です。
+func (a *RMSApi) SearchItem(cond *ItemSearchCondition) (*ItemSearchResponse, error) {
+	return a.SearchItemContext(context.Background(), cond)
+}
+
+// SearchItemContext は SearchItem にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) SearchItemContext(ctx context.Context, cond *ItemSearchCondition) (*ItemSearchResponse, error) {
+	if !a.initialized() {
+		return nil, ErrNotInitialized
+	}
+	params := url.Values{}
+	if cond == nil {
+		cond = &ItemSearchCondition{}
+	}
+	if cond.ItemName != "" {
+		params.Add("itemName", cond.ItemName)
+	}
+	if cond.ItemNumber != "" {
+		params.Add("itemNumber", cond.ItemNumber)
+	}
+	if cond.CatchCopy != "" {
+		params.Add("catchcopy", cond.CatchCopy)
+	}
+	if cond.GenreID != "" {
+		params.Add("genreId", cond.GenreID)
+	}
+	if cond.ItemPriceFrom != nil {
+		params.Add("itemPriceFrom", fmt.Sprintf("%d", *cond.ItemPriceFrom))
+	}
+	if cond.ItemPriceTo != nil {
+		params.Add("itemPriceTo", fmt.Sprintf("%d", *cond.ItemPriceTo))
+	}
+	if cond.DepotFlag != nil {
+		params.Add("depotFlag", fmt.Sprintf("%d", *cond.DepotFlag))
+	}
+	if cond.Offset > 0 {
+		params.Add("offset", fmt.Sprintf("%d", cond.Offset))
+	}
+	limit := cond.Limit
+	if limit <= 0 || limit > ITEM_SEARCH_MAX_LIMIT {
+		limit = ITEM_SEARCH_MAX_LIMIT
+	}
+	params.Add("limit", fmt.Sprintf("%d", limit))
+
+	result := ItemSearchResponse{}
+	if err := a.sendItemXML(ctx, "GET", ITEM_SEARCH_URL, params, nil, &result, &result.Result.ItemResult); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// InsertItem は商品APIで商品を登録します。item は登録する商品です。
+func (a *RMSApi) InsertItem(item *ItemModel) error {
+	return a.InsertItemContext(context.Background(), item)
+}
+
+// InsertItemContext は InsertItem にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) InsertItemContext(ctx context.Context, item *ItemModel) error {
+	if !a.initialized() {
+		return ErrNotInitialized
+	}
+	result := ItemUpdateResponse{InsertResult: &ItemResult{}}
+	return a.sendItemXML(ctx, "POST", ITEM_INSERT_URL, nil, &ItemRequest{Insert: item}, &result, result.InsertResult)
+}
+
+// UpdateItem は商品APIで商品を更新します。item は更新する商品で、ItemURL で対象の商品を指定します。
+func (a *RMSApi) UpdateItem(item *ItemModel) error {
+	return a.UpdateItemContext(context.Background(), item)
+}
+
+// UpdateItemContext は UpdateItem にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) UpdateItemContext(ctx context.Context, item *ItemModel) error {
+	if !a.initialized() {
+		return ErrNotInitialized
+	}
+	result := ItemUpdateResponse{UpdateResult: &ItemResult{}}
+	return a.sendItemXML(ctx, "POST", ITEM_UPDATE_URL, nil, &ItemRequest{Update: item}, &result, result.UpdateResult)
+}
+
+// DeleteItem は商品APIで商品を削除します。itemURL は商品管理番号です。
+func (a *RMSApi) DeleteItem(itemURL string) error {
+	return a.DeleteItemContext(context.Background(), itemURL)
+}
+
+// DeleteItemContext は DeleteItem にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) DeleteItemContext(ctx context.Context, itemURL string) error {
+	if !a.initialized() {
+		return ErrNotInitialized
+	}
+	result := ItemUpdateResponse{DeleteResult: &ItemResult{}}
+	return a.sendItemXML(ctx, "POST", ITEM_DELETE_URL, nil, &ItemRequest{Delete: &ItemDeleteCondition{itemURL}}, &result, result.DeleteResult)
+}
+
+// sendItemXML は商品APIにXMLのリクエストを送信し、レスポンスを result に格納します。r は result に含まれる処理結果で、結果コードがN000以外の場合は APIError を返却します。
+func (a *RMSApi) sendItemXML(ctx context.Context, method, u string, params url.Values, req interface{}, result interface{}, r *ItemResult) error {
+	var body []byte
+	if req != nil {
+		b, err := xml.Marshal(req)
+		if err != nil {
+			return err
+		}
+		body = append([]byte(xml.Header), b...)
+	}
+	status, byteArray, err := a.send(ctx, method, u, "text/xml; charset=utf-8", params, body)
+	if err != nil {
+		return err
+	}
+
+	err = xml.Unmarshal(byteArray, result)
+	if err != nil && isSuccessStatus(status) {
+		return err
+	}
+	if !isSuccessStatus(status) || r.Code != "N000" {
+		return newAPIError(status, byteArray, r.messageModelList())
+	}
+	return nil
+}
+
+// messageModelList は商品APIの処理結果を楽天ペイ受注APIと同じ形式に変換します。
+func (r *ItemResult) messageModelList() []CommonMessageModelResponse {
+	list := []CommonMessageModelResponse{}
+	if r.Code == "" {
+		return list
+	}
+	t := "ERROR"
+	if r.Code == "N000" {
+		t = "INFO"
+	}
+	list = append(list, CommonMessageModelResponse{MessageType: t, MessageCode: r.Code})
+	for _, m := range r.ErrorMessages {
+		list = append(list, CommonMessageModelResponse{MessageType: "ERROR", MessageCode: m.MsgCode, Message: fmt.Sprintf("%s: %s", m.FieldID, m.Msg)})
+	}
+	return list
+}
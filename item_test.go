@@ -0,0 +1,136 @@
+package rms
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGetItem_XMLの取得(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/es/1.0/item/get" || r.URL.Query().Get("itemUrl") != "hoge-001" {
+			t.Errorf("expected: /es/1.0/item/get?itemUrl=hoge-001, actual: %s", r.URL)
+		}
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<result>
+  <status><interfaceId>item.get</interfaceId><systemStatus>OK</systemStatus><message>OK</message><requestId>1</requestId></status>
+  <itemGetResult>
+    <code>N000</code>
+    <item>
+      <itemUrl>hoge-001</itemUrl>
+      <itemName>ほげ</itemName>
+      <itemPrice>1080</itemPrice>
+      <taxFlag>0</taxFlag>
+      <postageFlag>1</postageFlag>
+      <descriptionForPC>&lt;b&gt;ほげ&lt;/b&gt;</descriptionForPC>
+      <images><image><imageUrl>https://image.rakuten.co.jp/hoge/cabinet/1.jpg</imageUrl><imageAlt>ほげ</imageAlt></image></images>
+      <options><option><optionName>色</optionName><optionStyle>0</optionStyle><optionValues><optionValue><value>赤</value></optionValue><optionValue><value>青</value></optionValue></optionValues></option></options>
+    </item>
+  </itemGetResult>
+</result>`))
+	}))
+	defer ts.Close()
+
+	a := NewRMSApi("hoge", "fuga", WithBaseURL(ts.URL))
+	r, err := a.GetItem("hoge-001")
+	if err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	i := r.Result.Item
+	if i == nil || i.ItemPrice != 1080 || *i.PostageFlag != ITEM_POSTAGE_FLAG_INCLUDED || i.DescriptionForPC != "<b>ほげ</b>" {
+		t.Errorf("expected: hoge-001, actual: %v", i)
+		t.FailNow()
+	}
+	if len(i.Images) != 1 || len(i.Options) != 1 || len(i.Options[0].OptionValues) != 2 {
+		t.Errorf("expected: 1 image and 2 option values, actual: %v %v", i.Images, i.Options)
+	}
+}
+
+func TestInsertItem_XMLの送信とエラー(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		req := ItemRequest{}
+		if err := xml.Unmarshal(body, &req); err != nil || req.Insert == nil || req.Insert.ItemURL != "hoge-001" {
+			t.Errorf("expected: itemInsertRequest, actual: %s", body)
+		}
+		if !strings.Contains(string(body), "<taxFlag>1</taxFlag>") || strings.Contains(string(body), "postageFlag") {
+			t.Errorf("expected: only taxFlag, actual: %s", body)
+		}
+		w.Write([]byte(`<result><status><interfaceId>item.insert</interfaceId><systemStatus>OK</systemStatus></status>
+<itemInsertResult><code>C013</code><errorMessages><errorMessage><fieldId>itemPrice</fieldId><msgCode>ERR_002</msgCode><msg>価格が不正です。</msg></errorMessage></errorMessages></itemInsertResult></result>`))
+	}))
+	defer ts.Close()
+
+	a := NewRMSApi("hoge", "fuga", WithBaseURL(ts.URL))
+	tf := ITEM_TAX_FLAG_EXCLUDED
+	err := a.InsertItem(&ItemModel{ItemURL: "hoge-001", ItemName: "ほげ", ItemPrice: 1000, TaxFlag: &tf})
+	apiErr := &APIError{}
+	if !errors.As(err, &apiErr) || apiErr.MessageCode != "C013" || len(apiErr.MessageModelList) != 2 {
+		t.Errorf("expected: C013, actual: %v", err)
+	}
+}
+
+func TestItemV2_取得と登録と削除(t *testing.T) {
+	items := map[string]ItemV2Model{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mn := strings.TrimPrefix(r.URL.Path, "/es/2.0/items/manage-numbers/")
+		switch r.Method {
+		case "PUT":
+			i := ItemV2Model{}
+			json.NewDecoder(r.Body).Decode(&i)
+			if i.ManageNumber != "" {
+				t.Errorf("expected: empty manageNumber in body, actual: %s", i.ManageNumber)
+			}
+			i.ManageNumber = mn
+			items[mn] = i
+			w.WriteHeader(http.StatusNoContent)
+		case "GET":
+			i, ok := items[mn]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"errors":[{"code":"IE0124","message":"The item does not exist."}]}`))
+				return
+			}
+			json.NewEncoder(w).Encode(i)
+		case "DELETE":
+			delete(items, mn)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer ts.Close()
+
+	a := NewRMSApi("hoge", "fuga", WithBaseURL(ts.URL))
+	item := ItemV2Model{
+		ManageNumber: "hoge-001",
+		Title:        "ほげ",
+		ItemType:     "NORMAL",
+		Payment:      &ItemV2PaymentModel{TaxIncluded: true, TaxRate: "0.1"},
+		Variants:     map[string]ItemV2VariantModel{"sku-1": {StandardPrice: "1100"}},
+	}
+	if err := a.UpsertItemV2(&item); err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	r, err := a.GetItemV2("hoge-001")
+	if err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	if r.Title != "ほげ" || r.Variants["sku-1"].StandardPrice != "1100" {
+		t.Errorf("expected: ほげ 1100, actual: %v", r)
+	}
+	if err := a.DeleteItemV2("hoge-001"); err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	_, err = a.GetItemV2("hoge-001")
+	if !errors.Is(err, &APIError{MessageCode: "IE0124", StatusCode: http.StatusNotFound}) {
+		t.Errorf("expected: IE0124, actual: %v", err)
+	}
+}
@@ -0,0 +1,288 @@
+package rms
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+const (
+	// RMS WEB SERVICEの商品API 2.0の商品管理番号を指定した商品の取得・登録・削除用のエンドポイントです。末尾に商品管理番号を付与して使用します。
+	ITEMS_MANAGE_NUMBER_URL = "https://api.rms.rakuten.co.jp/es/2.0/items/manage-numbers/"
+
+	// RMS WEB SERVICEの商品API 2.0の商品検索用のエンドポイントです。
+	ITEMS_SEARCH_URL = "https://api.rms.rakuten.co.jp/es/2.0/items/search"
+)
+
+type (
+	// ItemV2DescriptionModel は商品API 2.0の商品説明文です。
+	ItemV2DescriptionModel struct {
+		// PC はPC用商品説明文です。HTMLを使用することができます。
+		PC string `json:"pc,omitempty"`
+
+		// SP はスマートフォン用商品説明文です。HTMLを使用することができます。
+		SP string `json:"sp,omitempty"`
+	}
+
+	// ItemV2ImageModel は商品API 2.0の商品画像です。
+	ItemV2ImageModel struct {
+		// Type は画像の種類です。CABINET もしくは GOLD を指定します。
+		Type string `json:"type"`
+
+		// Location は画像のパスです。
+		Location string `json:"location"`
+
+		// Alt は画像の説明(alt)です。
+		Alt string `json:"alt,omitempty"`
+	}
+
+	// ItemV2PaymentModel は商品API 2.0の支払いに関する設定です。
+	ItemV2PaymentModel struct {
+		// TaxIncluded は販売価格が税込みかどうかです。
+		TaxIncluded bool `json:"taxIncluded"`
+
+		// TaxRate は消費税率です。"0.1" のように指定します。
+		TaxRate string `json:"taxRate,omitempty"`
+
+		// CashOnDeliveryFeeIncluded は代引料込みかどうかです。
+		CashOnDeliveryFeeIncluded bool `json:"cashOnDeliveryFeeIncluded"`
+	}
+
+	// ItemV2SelectorValueModel は商品API 2.0のバリエーション項目の選択肢です。
+	ItemV2SelectorValueModel struct {
+		// DisplayValue は選択肢の表示名です。
+		DisplayValue string `json:"displayValue"`
+	}
+
+	// ItemV2VariantSelectorModel は商品API 2.0のバリエーション項目です。
+	ItemV2VariantSelectorModel struct {
+		// Key はバリエーション項目キーです。
+		Key string `json:"key"`
+
+		// DisplayName はバリエーション項目名です。
+		DisplayName string `json:"displayName"`
+
+		// Values は選択肢です。
+		Values []ItemV2SelectorValueModel `json:"values"`
+	}
+
+	// ItemV2ShippingModel は商品API 2.0のSKUごとの配送に関する設定です。
+	ItemV2ShippingModel struct {
+		// PostageIncluded は送料込みかどうかです。
+		PostageIncluded bool `json:"postageIncluded"`
+
+		// Fee は個別送料です。
+		Fee *int `json:"fee,omitempty"`
+	}
+
+	// ItemV2VariantModel は商品API 2.0のSKUです。
+	ItemV2VariantModel struct {
+		// SelectorValues はバリエーション項目キーごとの選択肢です。
+		SelectorValues map[string]string `json:"selectorValues,omitempty"`
+
+		// MerchantDefinedSkuID はSKU管理番号です。
+		MerchantDefinedSkuID string `json:"merchantDefinedSkuId,omitempty"`
+
+		// StandardPrice は販売価格です。
+		StandardPrice string `json:"standardPrice,omitempty"`
+
+		// ArticleNumber はカタログID(JANコード等)です。
+		ArticleNumber *struct {
+			Value string `json:"value,omitempty"`
+		} `json:"articleNumber,omitempty"`
+
+		// Hidden はSKUを非表示にするかどうかです。
+		Hidden bool `json:"hidden,omitempty"`
+
+		// Shipping は配送に関する設定です。
+		Shipping *ItemV2ShippingModel `json:"shipping,omitempty"`
+	}
+
+	// ItemV2Model は商品API 2.0で取り扱う商品です。
+	ItemV2Model struct {
+		// ManageNumber は商品管理番号です。
+		ManageNumber string `json:"manageNumber,omitempty"`
+
+		// ItemNumber は商品番号です。
+		ItemNumber string `json:"itemNumber,omitempty"`
+
+		// Title は商品名です。
+		Title string `json:"title"`
+
+		// Tagline はキャッチコピーです。
+		Tagline string `json:"tagline,omitempty"`
+
+		// ProductDescription は商品説明文です。
+		ProductDescription *ItemV2DescriptionModel `json:"productDescription,omitempty"`
+
+		// SalesDescription は販売説明文です。HTMLを使用することができます。
+		SalesDescription string `json:"salesDescription,omitempty"`
+
+		// ItemType は商品種別です。NORMAL、PRE_ORDER、SUBSCRIPTION のいずれかを指定します。
+		ItemType string `json:"itemType"`
+
+		// GenreID はジャンルIDです。
+		GenreID string `json:"genreId,omitempty"`
+
+		// Tags はタグIDです。
+		Tags []int `json:"tags,omitempty"`
+
+		// HideItem は倉庫指定です。true の場合は倉庫に入れます。
+		HideItem bool `json:"hideItem"`
+
+		// UnlimitedInventoryFlag は在庫数を無制限にするかどうかです。
+		UnlimitedInventoryFlag bool `json:"unlimitedInventoryFlag,omitempty"`
+
+		// Images は商品画像です。
+		Images []ItemV2ImageModel `json:"images,omitempty"`
+
+		// Payment は支払いに関する設定です。
+		Payment *ItemV2PaymentModel `json:"payment,omitempty"`
+
+		// VariantSelectors はバリエーション項目です。
+		VariantSelectors []ItemV2VariantSelectorModel `json:"variantSelectors,omitempty"`
+
+		// Variants はSKUです。キーはSKU管理番号です。
+		Variants map[string]ItemV2VariantModel `json:"variants,omitempty"`
+
+		// Created は登録日時です。取得の場合のみ入力されます。
+		Created string `json:"created,omitempty"`
+
+		// Updated は更新日時です。取得の場合のみ入力されます。
+		Updated string `json:"updated,omitempty"`
+	}
+
+	// ItemV2SearchCondition は商品API 2.0の商品検索の条件です。指定しない項目は条件に含まれません。
+	ItemV2SearchCondition struct {
+		// Title は商品名です。
+		Title string
+
+		// ManageNumber は商品管理番号です。
+		ManageNumber string
+
+		// GenreID はジャンルIDです。
+		GenreID string
+
+		// IsHiddenItem は倉庫指定です。
+		IsHiddenItem *bool
+
+		// Offset は取得を開始する位置です。
+		Offset int
+
+		// Hits は取得する商品の数です。1~100まで指定することができます。それ以外の場合は100件取得します。
+		Hits int
+	}
+
+	// ItemV2SearchResultModel は商品API 2.0の商品検索で得られる商品です。
+	ItemV2SearchResultModel struct {
+		// Item は商品です。
+		Item ItemV2Model `json:"item"`
+	}
+
+	// ItemV2SearchResponse は商品API 2.0の商品検索で得られるレスポンスです。
+	ItemV2SearchResponse struct {
+		// NumFound は条件に一致した商品の数です。
+		NumFound int `json:"numFound"`
+
+		// Offset は取得を開始した位置です。
+		Offset int `json:"offset"`
+
+		// Hits は取得した商品の数です。
+		Hits int `json:"hits"`
+
+		// Results は商品です。
+		Results []ItemV2SearchResultModel `json:"results"`
+	}
+)
+
+// GetItemV2 は商品API 2.0で商品を取得します。manageNumber は商品管理番号です。
+func (a *RMSApi) GetItemV2(manageNumber string) (*ItemV2Model, error) {
+	return a.GetItemV2Context(context.Background(), manageNumber)
+}
+
+// GetItemV2Context は GetItemV2 にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) GetItemV2Context(ctx context.Context, manageNumber string) (*ItemV2Model, error) {
+	if !a.initialized() {
+		return nil, ErrNotInitialized
+	}
+	result := ItemV2Model{}
+	if err := a.sendJSON(ctx, "GET", ITEMS_MANAGE_NUMBER_URL+url.PathEscape(manageNumber), nil, nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// UpsertItemV2 は商品API 2.0で商品を登録します。item の ManageNumber の商品が登録済みの場合は、item の内容で置き換えます。
+func (a *RMSApi) UpsertItemV2(item *ItemV2Model) error {
+	return a.UpsertItemV2Context(context.Background(), item)
+}
+
+// UpsertItemV2Context は UpsertItemV2 にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) UpsertItemV2Context(ctx context.Context, item *ItemV2Model) error {
+	if !a.initialized() {
+		return ErrNotInitialized
+	}
+	if item.ManageNumber == "" {
+		return &ValidationError{Field: "manageNumber", Message: "商品管理番号を指定してください。"}
+	}
+	body := *item
+	body.ManageNumber = ""
+	body.Created = ""
+	body.Updated = ""
+	return a.sendJSON(ctx, "PUT", ITEMS_MANAGE_NUMBER_URL+url.PathEscape(item.ManageNumber), nil, body, nil)
+}
+
+// DeleteItemV2 は商品API 2.0で商品を削除します。manageNumber は商品管理番号です。
+func (a *RMSApi) DeleteItemV2(manageNumber string) error {
+	return a.DeleteItemV2Context(context.Background(), manageNumber)
+}
+
+// DeleteItemV2Context は DeleteItemV2 にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) DeleteItemV2Context(ctx context.Context, manageNumber string) error {
+	if !a.initialized() {
+		return ErrNotInitialized
+	}
+	return a.sendJSON(ctx, "DELETE", ITEMS_MANAGE_NUMBER_URL+url.PathEscape(manageNumber), nil, nil, nil)
+}
+
+// SearchItemV2 は商品API 2.0で商品を検索します。cond は検索条件です。
+func (a *RMSApi) SearchItemV2(cond *ItemV2SearchCondition) (*ItemV2SearchResponse, error) {
+	return a.SearchItemV2Context(context.Background(), cond)
+}
+
+// SearchItemV2Context は SearchItemV2 にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) SearchItemV2Context(ctx context.Context, cond *ItemV2SearchCondition) (*ItemV2SearchResponse, error) {
+	if !a.initialized() {
+		return nil, ErrNotInitialized
+	}
+	if cond == nil {
+		cond = &ItemV2SearchCondition{}
+	}
+	params := url.Values{}
+	if cond.Title != "" {
+		params.Add("title", cond.Title)
+	}
+	if cond.ManageNumber != "" {
+		params.Add("manageNumber", cond.ManageNumber)
+	}
+	if cond.GenreID != "" {
+		params.Add("genreId", cond.GenreID)
+	}
+	if cond.IsHiddenItem != nil {
+		params.Add("isHiddenItem", fmt.Sprintf("%t", *cond.IsHiddenItem))
+	}
+	if cond.Offset > 0 {
+		params.Add("offset", fmt.Sprintf("%d", cond.Offset))
+	}
+	hits := cond.Hits
+	if hits <= 0 || hits > ITEM_SEARCH_MAX_LIMIT {
+		hits = ITEM_SEARCH_MAX_LIMIT
+	}
+	params.Add("hits", fmt.Sprintf("%d", hits))
+
+	result := ItemV2SearchResponse{}
+	if err := a.sendJSON(ctx, "GET", ITEMS_SEARCH_URL, params, nil, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
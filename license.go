@@ -0,0 +1,43 @@
+package rms
+
+import (
+	"context"
+	"net/url"
+	"time"
+)
+
+const (
+	// RMS WEB SERVICEのライセンス管理APIのライセンスキーの有効期限取得用のエンドポイントです。
+	LICENSE_KEY_EXPIRY_DATE_URL = "https://api.rms.rakuten.co.jp/es/1.0/license-management/license-key/expiry-date"
+)
+
+// LicenseKeyExpiryDateResponse はライセンス管理APIのライセンスキーの有効期限取得で得られるレスポンスです。
+type LicenseKeyExpiryDateResponse struct {
+	// ExpiryDate はライセンスキーの有効期限です。
+	ExpiryDate JsonDate `json:"expiryDate"`
+}
+
+// GetLicenseKeyExpiryDate はライセンス管理APIで、現在使用しているライセンスキーの有効期限を取得します。
+// 有効期限を過ぎたライセンスキーではすべてのAPIが認証エラー(ErrUnauthorized)になるため、定期的に確認して事前に差し替えてください。
+func (a *RMSApi) GetLicenseKeyExpiryDate() (time.Time, error) {
+	return a.GetLicenseKeyExpiryDateContext(context.Background())
+}
+
+// GetLicenseKeyExpiryDateContext は GetLicenseKeyExpiryDate にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) GetLicenseKeyExpiryDateContext(ctx context.Context) (time.Time, error) {
+	if !a.initialized() {
+		return time.Time{}, ErrNotInitialized
+	}
+	c, err := a.currentCredentials(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	params := url.Values{}
+	params.Add("licenseKey", c.licenseKey)
+	result := LicenseKeyExpiryDateResponse{}
+	// 確認するライセンスキーと認証に使用するライセンスキーが食い違わないように、取得した認証情報で送信します。
+	if err := a.sendJSON(withCredentials(ctx, c), "GET", LICENSE_KEY_EXPIRY_DATE_URL, params, nil, &result); err != nil {
+		return time.Time{}, err
+	}
+	return result.ExpiryDate.Time, nil
+}
@@ -4,12 +4,11 @@
 package rms
 
 import (
-	"bytes"
-	"encoding/base64"
+	"context"
 	"encoding/json"
-	"errors"
-	"io/ioutil"
+	"fmt"
 	"net/http"
+	"sync/atomic"
 	"time"
 )
 
@@ -25,6 +24,42 @@ const (
 
 	// RMS WEB SERVICEの楽天ペイ受注APIの発送情報の追加・更新用のエンドポイントです。
 	UPDATE_ORDER_SHIPPING_URL = "https://api.rms.rakuten.co.jp/es/2.0/order/updateOrderShipping/"
+
+	// RMS WEB SERVICEの楽天ペイ受注APIの発送情報の一括追加・更新(非同期)用のエンドポイントです。
+	UPDATE_ORDER_SHIPPING_ASYNC_URL = "https://api.rms.rakuten.co.jp/es/2.0/order/updateOrderShippingAsync/"
+
+	// RMS WEB SERVICEの楽天ペイ受注APIの発送情報の一括追加・更新(非同期)の結果取得用のエンドポイントです。
+	GET_RESULT_UPDATE_ORDER_SHIPPING_ASYNC_URL = "https://api.rms.rakuten.co.jp/es/2.0/order/getResultUpdateOrderShippingAsync/"
+
+	// RMS WEB SERVICEの楽天ペイ受注APIの注文確認用のエンドポイントです。
+	CONFIRM_ORDER_URL = "https://api.rms.rakuten.co.jp/es/2.0/order/confirmOrder/"
+
+	// RMS WEB SERVICEの楽天ペイ受注APIの注文キャンセル用のエンドポイントです。
+	CANCEL_ORDER_URL = "https://api.rms.rakuten.co.jp/es/2.0/order/cancelOrder/"
+
+	// RMS WEB SERVICEの楽天ペイ受注APIの決済情報の取得用のエンドポイントです。
+	GET_PAYMENT_URL = "https://api.rms.rakuten.co.jp/es/2.0/order/getPayment/"
+
+	// RMS WEB SERVICEの楽天ペイ受注APIのサブステータス一覧取得用のエンドポイントです。
+	GET_SUB_STATUS_LIST_URL = "https://api.rms.rakuten.co.jp/es/2.0/order/getSubStatusList/"
+
+	// RMS WEB SERVICEの楽天ペイ受注APIのサブステータス更新用のエンドポイントです。
+	UPDATE_ORDER_SUB_STATUS_URL = "https://api.rms.rakuten.co.jp/es/2.0/order/updateOrderSubStatus/"
+
+	// RMS WEB SERVICEの楽天ペイ受注APIの送付先情報の更新用のエンドポイントです。
+	UPDATE_ORDER_SENDER_URL = "https://api.rms.rakuten.co.jp/es/2.0/order/updateOrderSender/"
+
+	// RMS WEB SERVICEの楽天ペイ受注APIの注文者情報の更新用のエンドポイントです。
+	UPDATE_ORDER_ORDERER_URL = "https://api.rms.rakuten.co.jp/es/2.0/order/updateOrderOrderer/"
+
+	// RMS WEB SERVICEの楽天ペイ受注APIの備考欄の更新用のエンドポイントです。
+	UPDATE_ORDER_REMARKS_URL = "https://api.rms.rakuten.co.jp/es/2.0/order/updateOrderRemarks/"
+
+	// CONFIRM_ORDER_MAX_ORDERS は楽天ペイ受注APIの注文確認で一度に指定できる注文番号の数です。
+	CONFIRM_ORDER_MAX_ORDERS = 100
+
+	// UPDATE_ORDER_SUB_STATUS_MAX_ORDERS は楽天ペイ受注APIのサブステータス更新で一度に指定できる注文番号の数です。
+	UPDATE_ORDER_SUB_STATUS_MAX_ORDERS = 100
 )
 
 // SearchOrderDateType は期間検索種別を表します。
@@ -39,6 +74,38 @@ const (
 	DATE_TYPE_PAYMENT_FIX_DATE                         // 決済確定日
 )
 
+// CancelReason は注文キャンセル時のキャンセル理由です。GetOrderChangeReasonModel の ChangeReasonDetail に対応します。
+type CancelReason int
+
+const (
+	CANCEL_REASON_CANCEL            CancelReason = 1  // キャンセル
+	CANCEL_REASON_RETURNED          CancelReason = 2  // 受取後の返品
+	CANCEL_REASON_LONG_ABSENCE      CancelReason = 3  // 長期不在による受取拒否
+	CANCEL_REASON_UNPAID            CancelReason = 4  // 未入金
+	CANCEL_REASON_COD_REFUSED       CancelReason = 5  // 代引決済の受取拒否
+	CANCEL_REASON_CUSTOMER_OTHER    CancelReason = 6  // お客様都合 - その他
+	CANCEL_REASON_OUT_OF_STOCK      CancelReason = 8  // 欠品
+	CANCEL_REASON_SHOP_OTHER        CancelReason = 10 // 店舗様都合 - その他
+	CANCEL_REASON_SHIPPING_DELAY    CancelReason = 13 // 発送遅延
+	CANCEL_REASON_CAUTION           CancelReason = 14 // 顧客・配送対応注意表示
+	CANCEL_REASON_RETURNED_DEFECTED CancelReason = 15 // 返品(破損・品間違い)
+)
+
+// InventoryRestoreType は注文キャンセル時の在庫連動区分です。
+type InventoryRestoreType int
+
+const (
+	INVENTORY_RESTORE_TYPE_RESTORE    InventoryRestoreType = 1 // 在庫連動する
+	INVENTORY_RESTORE_TYPE_NO_RESTORE InventoryRestoreType = 2 // 在庫連動しない
+)
+
+// cancellableOrderProgress はキャンセルすることができるステータスです。
+var cancellableOrderProgress = map[int]bool{
+	100: true, // 注文確認待ち
+	300: true, // 発送待ち
+	400: true, // 変更確定待ち
+}
+
 type (
 	/*** RMSとの通信時に使用 ***/
 	/*** 共通 ***/
@@ -920,7 +987,7 @@ type (
 		CancelDueDate *JsonDate `json:"cancelDueDate"`
 
 		// DeliveryDate はお届け日指定です。
-		DeliveryDate *JsonDate `json"deliveryDate"`
+		DeliveryDate *JsonDate `json:"deliveryDate"`
 
 		// ShippingTerm はお届け時間帯です。以下のいずれかが入力されます。
 		// 0: なし
@@ -990,7 +1057,7 @@ type (
 		OrderType int `json:"orderType"`
 
 		// ReserveNumber は申込番号です。定期購入、頒布会、予約商品に付与されます。
-		ReserveNumber *string `json:reserveNumber"`
+		ReserveNumber *string `json:"reserveNumber"`
 
 		// ReserveDeliveryCount は申込お届け回数です。予約商品は常に1、定期購入、頒布会は確定した回数が入力されます。
 		ReserveDeliveryCount *int `json:"reserveDeliveryCount"`
@@ -1031,7 +1098,7 @@ type (
 		RequestPrice int `json:"requestPrice"`
 
 		// CouponAllTotalPrice はクーポン利用総額です。
-		CouponAllTotalPrice int `json:"requestPrice"`
+		CouponAllTotalPrice int `json:"couponAllTotalPrice"`
 
 		// CouponShopPrice は店舗発行クーポン利用額です。クーポン原資コードが1のクーポンが対象です。未確定の場合は-9999です。
 		CouponShopPrice int `json:"couponShopPrice"`
@@ -1098,10 +1165,10 @@ type (
 		GetOrderPointModel `json:"PointModel"`
 
 		// WrappingModel1 はラッピングモデル1です。
-		WrappingModel1 GetOrderWrappingModel `json:"WrappingModel"`
+		WrappingModel1 GetOrderWrappingModel `json:"WrappingModel1"`
 
 		// WrappingModel2 はラッピングモデル2です。
-		WrappingModel2 GetOrderWrappingModel `json:"WrappingModel"`
+		WrappingModel2 GetOrderWrappingModel `json:"WrappingModel2"`
 
 		// PackageModelList は送付先モデルリストです。
 		PackageModelList []GetOrderPackageModel `json:"PackageModelList"`
@@ -1161,12 +1228,260 @@ type (
 		MessageModelList []UpdateOrderShippingMessageModel `json:"MessageModelList"`
 	}
 
+	/*** confirmOrder ***/
+
+	// ConfirmOrderRequest は楽天ペイ受注APIの注文確認のリクエストです。
+	ConfirmOrderRequest struct {
+		// OrderNumberList は注文番号リストです。最大100件まで指定可能です。
+		OrderNumberList []string `json:"orderNumberList"`
+	}
+
+	// ConfirmOrderMessageModel は楽天ペイ受注APIの注文確認で得られる注文ごとの結果です。
+	ConfirmOrderMessageModel struct {
+		// CommonMessageModelResponse は結果の情報が含まれます。
+		CommonMessageModelResponse
+
+		// OrderNumber は注文番号です。
+		OrderNumber string `json:"orderNumber"`
+	}
+
+	// ConfirmOrderResponse は楽天ペイ受注APIの注文確認で得られるレスポンスです。
+	ConfirmOrderResponse struct {
+		// MessageModelList はメッセージモデルリストです。注文ごとの結果が含まれます。
+		MessageModelList []ConfirmOrderMessageModel `json:"MessageModelList"`
+	}
+
+	/*** cancelOrder ***/
+
+	// CancelOrderCondition は楽天ペイ受注APIの注文キャンセルの内容です。
+	CancelOrderCondition struct {
+		// OrderNumber は注文番号です。
+		OrderNumber string `json:"orderNumber"`
+
+		// InventoryRestoreType は在庫連動区分です。
+		InventoryRestoreType InventoryRestoreType `json:"inventoryRestoreType"`
+
+		// ChangeReasonDetailApply はキャンセル理由です。
+		ChangeReasonDetailApply CancelReason `json:"changeReasonDetailApply"`
+	}
+
+	// CancelOrderMessageModel は楽天ペイ受注APIの注文キャンセルで得られる結果です。
+	CancelOrderMessageModel struct {
+		// CommonMessageModelResponse は結果の情報が含まれます。
+		CommonMessageModelResponse
+
+		// OrderNumber は注文番号です。
+		OrderNumber string `json:"orderNumber"`
+	}
+
+	// CancelOrderResponse は楽天ペイ受注APIの注文キャンセルで得られるレスポンスです。
+	CancelOrderResponse struct {
+		// MessageModelList はメッセージモデルリストです。
+		MessageModelList []CancelOrderMessageModel `json:"MessageModelList"`
+	}
+
+	/*** getSubStatusList ***/
+
+	// SubStatus は店舗が作成したサブステータスです。
+	SubStatus struct {
+		// SubStatusID はサブステータスIDです。
+		SubStatusID int `json:"subStatusId"`
+
+		// SubStatusName はサブステータス名です。
+		SubStatusName string `json:"subStatusName"`
+
+		// OrderBy は表示順です。
+		OrderBy int `json:"orderby"`
+
+		// Rgb は表示色です。
+		Rgb *string `json:"rgb"`
+	}
+
+	// GetSubStatusListResponse は楽天ペイ受注APIのサブステータス一覧取得で得られるレスポンスです。
+	GetSubStatusListResponse struct {
+		// MessageModelList はメッセージモデルリストです。
+		MessageModelList []CommonMessageModelResponse `json:"MessageModelList"`
+
+		// SubStatusModelList はサブステータスモデルリストです。
+		SubStatusModelList []SubStatus `json:"subStatusModelList"`
+	}
+
+	/*** updateOrderSubStatus ***/
+
+	// UpdateOrderSubStatusRequest は楽天ペイ受注APIのサブステータス更新のリクエストです。
+	UpdateOrderSubStatusRequest struct {
+		// SubStatusID は移動先のサブステータスIDです。
+		SubStatusID int `json:"subStatusId"`
+
+		// OrderNumberList は注文番号リストです。最大100件まで指定可能です。
+		OrderNumberList []string `json:"orderNumberList"`
+	}
+
+	// UpdateOrderSubStatusMessageModel は楽天ペイ受注APIのサブステータス更新で得られる注文ごとの結果です。
+	UpdateOrderSubStatusMessageModel struct {
+		// CommonMessageModelResponse は結果の情報が含まれます。
+		CommonMessageModelResponse
+
+		// OrderNumber は注文番号です。
+		OrderNumber string `json:"orderNumber"`
+	}
+
+	// UpdateOrderSubStatusResponse は楽天ペイ受注APIのサブステータス更新で得られるレスポンスです。
+	UpdateOrderSubStatusResponse struct {
+		// MessageModelList はメッセージモデルリストです。注文ごとの結果が含まれます。
+		MessageModelList []UpdateOrderSubStatusMessageModel `json:"MessageModelList"`
+	}
+
+	/*** updateOrderSender ***/
+
+	// UpdateOrderSenderModelCondition は楽天ペイ受注APIの送付先情報の更新の送付者モデルです。項目は GetOrderSenderModel と同じです。
+	UpdateOrderSenderModelCondition struct {
+		// ZipCode1 は郵便番号1です。3桁の数値です。(0始まりがあるため文字列です。)
+		ZipCode1 string `json:"zipCode1"`
+
+		// ZipCode2 は郵便番号2です。4桁の数値です。(0始まりがあるため文字列です。)
+		ZipCode2 string `json:"zipCode2"`
+
+		// Prefecture は都道府県です。
+		Prefecture string `json:"prefecture"`
+
+		// City は郡市区です。
+		City string `json:"city"`
+
+		// SubAddress はCity以降の住所です。
+		SubAddress string `json:"subAddress"`
+
+		// FamilyName は姓です。
+		FamilyName string `json:"familyName"`
+
+		// FirstName は名です。
+		FirstName string `json:"firstName"`
+
+		// FamilyNameKana は姓カナです。
+		FamilyNameKana *string `json:"familyNameKana,omitempty"`
+
+		// FirstNameKana は名カナです。
+		FirstNameKana *string `json:"firstNameKana,omitempty"`
+
+		// PhoneNumber1 は電話番号1です。
+		PhoneNumber1 string `json:"phoneNumber1"`
+
+		// PhoneNumber2 は電話番号2です。
+		PhoneNumber2 string `json:"phoneNumber2"`
+
+		// PhoneNumber3 は電話番号3です。
+		PhoneNumber3 string `json:"phoneNumber3"`
+
+		// IsolatedIslandFlag は離島フラグです。以下のいずれかを指定することができます。
+		// 0: 離島ではない
+		// 1: 離島である
+		IsolatedIslandFlag int `json:"isolatedIslandFlag"`
+	}
+
+	// UpdateOrderDeliveryPackageModelCondition は楽天ペイ受注APIの送付先情報の更新の送付先モデルです。
+	UpdateOrderDeliveryPackageModelCondition struct {
+		// BasketID は送付先IDです。
+		BasketID int `json:"basketId"`
+
+		// SenderModel は送付者モデルです。
+		SenderModel UpdateOrderSenderModelCondition `json:"SenderModel"`
+	}
+
+	// UpdateOrderDeliveryCondition は楽天ペイ受注APIの送付先情報の更新を行うための条件です。
+	UpdateOrderDeliveryCondition struct {
+		// OrderNumber は注文番号です。
+		OrderNumber string `json:"orderNumber"`
+
+		// PackageModelList は送付先モデルリストです。
+		PackageModelList []UpdateOrderDeliveryPackageModelCondition `json:"PackageModelList"`
+	}
+
+	/*** updateOrderOrderer ***/
+
+	// UpdateOrderOrdererModelCondition は楽天ペイ受注APIの注文者情報の更新の注文者モデルです。項目は GetOrderOrdererModel と同じですが、メールアドレスは更新できません。
+	UpdateOrderOrdererModelCondition struct {
+		// ZipCode1 は郵便番号1です。3桁の数値です。(0はじまりがあるため文字列です。)
+		ZipCode1 string `json:"zipCode1"`
+
+		// ZipCode2 は郵便番号2です。4桁の数値です。(0はじまりがあるため文字列です。)
+		ZipCode2 string `json:"zipCode2"`
+
+		// Prefecture は都道府県です。
+		Prefecture string `json:"prefecture"`
+
+		// City は郡市区です。
+		City string `json:"city"`
+
+		// SubAddress はCity以降の住所です。
+		SubAddress string `json:"subAddress"`
+
+		// FamilyName は姓です。
+		FamilyName string `json:"familyName"`
+
+		// FirstName は名です。
+		FirstName string `json:"firstName"`
+
+		// FamilyNameKana は姓カナです。
+		FamilyNameKana *string `json:"familyNameKana,omitempty"`
+
+		// FirstNameKana は名カナです。
+		FirstNameKana *string `json:"firstNameKana,omitempty"`
+
+		// PhoneNumber1 は電話番号1です。
+		PhoneNumber1 string `json:"phoneNumber1"`
+
+		// PhoneNumber2 は電話番号2です。
+		PhoneNumber2 string `json:"phoneNumber2"`
+
+		// PhoneNumber3 は電話番号3です。
+		PhoneNumber3 string `json:"phoneNumber3"`
+
+		// Sex は性別です。
+		Sex string `json:"sex"`
+
+		// BirthYear は誕生日(年)です。
+		BirthYear int `json:"birthYear"`
+
+		// BirthMonth は誕生日(月)です。
+		BirthMonth int `json:"birthMonth"`
+
+		// BirthDay は誕生日(日)です。
+		BirthDay int `json:"birthDay"`
+	}
+
+	// UpdateOrderOrdererCondition は楽天ペイ受注APIの注文者情報の更新を行うための条件です。
+	UpdateOrderOrdererCondition struct {
+		// OrderNumber は注文番号です。
+		OrderNumber string `json:"orderNumber"`
+
+		// OrdererModel は注文者モデルです。
+		OrdererModel UpdateOrderOrdererModelCondition `json:"OrdererModel"`
+	}
+
+	/*** updateOrderRemarks ***/
+
+	// UpdateOrderRemarksCondition は楽天ペイ受注APIの備考欄の更新を行うための条件です。
+	UpdateOrderRemarksCondition struct {
+		// OrderNumber は注文番号です。
+		OrderNumber string `json:"orderNumber"`
+
+		// Remarks は備考欄のコメントです。
+		Remarks string `json:"remarks"`
+	}
+
 	/*** 内部メソッド ***/
 
+	// RMSApi はRMS WEB SERVICEのクライアントです。NewRMSApi で生成するか、Initialize で初期化してから使用してください。
 	RMSApi struct {
-		serviceSecret string
-		licenseKey    string
-		authorization string
+		// credentials は現在の認証情報(*credentials)です。Initialize で差し替えられるため、atomic.Value で保持します。
+		credentials         atomic.Value
+		credentialsProvider CredentialsProvider
+
+		client       *http.Client
+		transport    http.RoundTripper
+		baseURL      string
+		retryPolicy  *RetryPolicy
+		rateLimiters map[EndpointGroup]*RateLimiter
 	}
 
 	// SearchOrderCondition は楽天ペイ受注APIの注文検索の必須以外の検索条件です。
@@ -1389,18 +1704,16 @@ type (
 	}
 )
 
-// Initialize はSDKを初期化します。ssはサービスシークレット、lkはライセンスキーです。サービスシークレット、ライセンスキーは https://webservice.rms.rakuten.co.jp/merchant-portal/configurationApi のページから確認してください。
-func (a *RMSApi) Initialize(ss, lk string) {
-	a.serviceSecret = ss
-	a.licenseKey = lk
-	a.authorization = base64.StdEncoding.EncodeToString([]byte(a.serviceSecret + ":" + a.licenseKey))
-}
-
 // SearchOrder は楽天ペイ受注APIで注文を検索します。注文の検索では日付を指定して検索しなければいけません。dateType は期間検索種別で、startDatetime は開始日、endDatetime は終了日です。開始日は2年以内、終了日は開始日から63日以内を指定する必要があります。
 // それ以外の任意の検索条件は cond を通して指定することができます。
 func (a *RMSApi) SearchOrder(dateType SearchOrderDateType, startDatetime, endDatetime time.Time, cond *SearchOrderCondition) (*SearchOrderResponse, error) {
-	if a.authorization == "" {
-		return nil, errors.New("Uninitialized")
+	return a.SearchOrderContext(context.Background(), dateType, startDatetime, endDatetime, cond)
+}
+
+// SearchOrderContext は SearchOrder にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) SearchOrderContext(ctx context.Context, dateType SearchOrderDateType, startDatetime, endDatetime time.Time, cond *SearchOrderCondition) (*SearchOrderResponse, error) {
+	if !a.initialized() {
+		return nil, ErrNotInitialized
 	}
 	reqBody := SearchOrderReuquest{}
 	// For Required
@@ -1431,12 +1744,12 @@ func (a *RMSApi) SearchOrder(dateType SearchOrderDateType, startDatetime, endDat
 		}
 		if len(cond.OrderTypeList) > 0 {
 			for _, v := range cond.OrderTypeList {
-				if containsArray([]int{1, 4, 5, 6}, v) {
+				if containsInt([]int{1, 4, 5, 6}, v) {
 					reqBody.OrderTypeList = append(reqBody.OrderTypeList, v)
 				}
 			}
 		}
-		if containsArray([]int{1, 2, 3, 4, 5, 6, 7, 9, 12, 13, 14, 16, 17, 21}, cond.SettlementMethod) {
+		if containsInt([]int{1, 2, 3, 4, 5, 6, 7, 9, 12, 13, 14, 16, 17, 21}, cond.SettlementMethod) {
 			reqBody.SettlementMethod = &cond.SettlementMethod
 		}
 		if cond.DeliveryName != "" {
@@ -1494,128 +1807,409 @@ func (a *RMSApi) SearchOrder(dateType SearchOrderDateType, startDatetime, endDat
 		}
 	}
 
-	jsonStr, _ := json.Marshal(reqBody)
-
-	req, _ := http.NewRequest("POST", SEARCH_ORDER_URL, bytes.NewBuffer(jsonStr))
-	req.Header.Set("Authorization", "ESA "+a.authorization)
-	req.Header.Set("Content-Type", "application/json; charset=utf-8")
-
-	client := new(http.Client)
-	resp, err := client.Do(req)
-	defer resp.Body.Close()
+	status, byteArray, err := a.postJSON(ctx, SEARCH_ORDER_URL, reqBody)
 	if err != nil {
 		return nil, err
 	}
 
-	byteArray, _ := ioutil.ReadAll(resp.Body)
 	result := SearchOrderResponse{}
 	err = json.Unmarshal(byteArray, &result)
-	if err != nil {
+	if err != nil && isSuccessStatus(status) {
 		return nil, err
 	}
-	if len(result.CommonMessageModelResponseList) == 0 {
-		return nil, errors.New("Uninitialized")
+	if !isSuccessStatus(status) || len(result.CommonMessageModelResponseList) == 0 {
+		return nil, newAPIError(status, byteArray, result.CommonMessageModelResponseList)
 	}
 	return &result, nil
 }
 
 // GetOrder は楽天ペイ受注APIで注文情報を取得します。 oList は注文番号、v はバージョン番号です。バージョン番号は現在4まで指定することが可能です。
 func (a *RMSApi) GetOrder(oList []string, v int) (*GetOrderResponse, error) {
-	if a.authorization == "" {
-		return nil, errors.New("Uninitialized")
+	return a.GetOrderContext(context.Background(), oList, v)
+}
+
+// GetOrderContext は GetOrder にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) GetOrderContext(ctx context.Context, oList []string, v int) (*GetOrderResponse, error) {
+	if !a.initialized() {
+		return nil, ErrNotInitialized
 	}
 	reqBody := GetOrderRequest{}
 	// For Required
 	reqBody.OrderNumberList = oList
 	reqBody.Version = v
 
-	jsonStr, _ := json.Marshal(reqBody)
-
-	req, _ := http.NewRequest("POST", GET_ORDER_URL, bytes.NewBuffer(jsonStr))
-	req.Header.Set("Authorization", "ESA "+a.authorization)
-	req.Header.Set("Content-Type", "application/json; charset=utf-8")
-
-	client := new(http.Client)
-	resp, err := client.Do(req)
-	defer resp.Body.Close()
+	status, byteArray, err := a.postJSON(ctx, GET_ORDER_URL, reqBody)
 	if err != nil {
 		return nil, err
 	}
 
-	byteArray, _ := ioutil.ReadAll(resp.Body)
 	result := GetOrderResponse{}
 	err = json.Unmarshal(byteArray, &result)
-	if err != nil {
+	if err != nil && isSuccessStatus(status) {
 		return nil, err
 	}
-	if len(result.GetOrderMessageModelList) == 0 {
-		return nil, errors.New("Uninitialized")
+	if !isSuccessStatus(status) || len(result.GetOrderMessageModelList) == 0 {
+		list := []CommonMessageModelResponse{}
+		for _, m := range result.GetOrderMessageModelList {
+			list = append(list, m.CommonMessageModelResponse)
+		}
+		e := newAPIError(status, byteArray, list)
+		e.OrderErrors = result.OrderErrors()
+		return nil, e
 	}
 	return &result, nil
 }
 
 // UpdateOrderMemo は楽天ペイ受注APIでひとことメモを更新します。 cond は変更対象のデータです。
 func (a *RMSApi) UpdateOrderMemo(cond *UpdateOrderMemoCondition) error {
-	if a.authorization == "" {
-		return errors.New("Uninitialized")
-	}
-	jsonStr, _ := json.Marshal(*cond)
+	return a.UpdateOrderMemoContext(context.Background(), cond)
+}
 
-	req, _ := http.NewRequest("POST", UPDATE_ORDER_MEMO_URL, bytes.NewBuffer(jsonStr))
-	req.Header.Set("Authorization", "ESA "+a.authorization)
-	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+// UpdateOrderMemoContext は UpdateOrderMemo にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) UpdateOrderMemoContext(ctx context.Context, cond *UpdateOrderMemoCondition) error {
+	if !a.initialized() {
+		return ErrNotInitialized
+	}
+	return a.updateOrder(ctx, UPDATE_ORDER_MEMO_URL, *cond)
+}
 
-	client := new(http.Client)
-	resp, err := client.Do(req)
-	defer resp.Body.Close()
+// updateOrder は注文番号ごとの結果が MessageModelList で返却される更新系のエンドポイントを呼び出します。
+// いずれかの結果が INFO でない場合は、その注文の OrderError を含む APIError を返却します。
+func (a *RMSApi) updateOrder(ctx context.Context, u string, body interface{}) error {
+	status, byteArray, err := a.postJSON(ctx, u, body)
 	if err != nil {
 		return err
 	}
 
-	byteArray, _ := ioutil.ReadAll(resp.Body)
 	result := UpdateOrderMemoResponse{}
 	err = json.Unmarshal(byteArray, &result)
-	if err != nil {
+	if err != nil && isSuccessStatus(status) {
 		return err
 	}
-	if len(result.MessageModelList) == 0 {
-		return errors.New("Uninitialized")
+	list := []CommonMessageModelResponse{}
+	orderErrors := []*OrderError{}
+	for _, m := range result.MessageModelList {
+		list = append(list, m.CommonMessageModelResponse)
+		if m.MessageType != "INFO" {
+			orderErrors = append(orderErrors, &OrderError{m.CommonMessageModelResponse, m.OrderNumber})
+		}
 	}
-	if result.MessageModelList[0].MessageType != "INFO" {
-		return errors.New(result.MessageModelList[0].Message)
+	if !isSuccessStatus(status) || len(list) == 0 || len(orderErrors) > 0 {
+		e := newAPIError(status, byteArray, list)
+		e.OrderErrors = orderErrors
+		return e
 	}
 	return nil
 }
 
 // UpdateOrderShipping は楽天ペイ受注APIで「発送情報の追加・更新」を行うことができます。
 func (a *RMSApi) UpdateOrderShipping(cond *UpdateOrderShippingCondition) error {
-	if a.authorization == "" {
-		return errors.New("Uninitialized")
-	}
-	jsonStr, _ := json.Marshal(*cond)
-
-	req, _ := http.NewRequest("POST", UPDATE_ORDER_SHIPPING_URL, bytes.NewBuffer(jsonStr))
-	req.Header.Set("Authorization", "ESA "+a.authorization)
-	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	return a.UpdateOrderShippingContext(context.Background(), cond)
+}
 
-	client := new(http.Client)
-	resp, err := client.Do(req)
-	defer resp.Body.Close()
+// UpdateOrderShippingContext は UpdateOrderShipping にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) UpdateOrderShippingContext(ctx context.Context, cond *UpdateOrderShippingCondition) error {
+	if !a.initialized() {
+		return ErrNotInitialized
+	}
+	status, byteArray, err := a.postJSON(ctx, UPDATE_ORDER_SHIPPING_URL, *cond)
 	if err != nil {
 		return err
 	}
 
-	byteArray, _ := ioutil.ReadAll(resp.Body)
 	result := UpdateOrderShippingResponse{}
 	err = json.Unmarshal(byteArray, &result)
-	if err != nil {
+	if err != nil && isSuccessStatus(status) {
 		return err
 	}
-	if len(result.MessageModelList) == 0 {
-		return errors.New("Uninitialized")
+	list := []CommonMessageModelResponse{}
+	orderErrors := []*OrderError{}
+	for _, m := range result.MessageModelList {
+		list = append(list, m.CommonMessageModelResponse)
+		if m.MessageType != "INFO" {
+			orderErrors = append(orderErrors, &OrderError{m.CommonMessageModelResponse, cond.OrderNumber})
+		}
 	}
-	if result.MessageModelList[0].MessageType != "INFO" {
-		return errors.New(result.MessageModelList[0].Message)
+	if !isSuccessStatus(status) || len(list) == 0 || len(orderErrors) > 0 {
+		e := newAPIError(status, byteArray, list)
+		e.OrderErrors = orderErrors
+		return e
 	}
 	return nil
 }
+
+// OrderErrors はレスポンスのメッセージモデルリストのうち、注文番号が含まれるエラーを OrderError として返却します。
+func (r *GetOrderResponse) OrderErrors() []*OrderError {
+	errs := []*OrderError{}
+	for _, m := range r.GetOrderMessageModelList {
+		if m.MessageType == "ERROR" && m.OrderNumber != "" {
+			errs = append(errs, &OrderError{m.CommonMessageModelResponse, m.OrderNumber})
+		}
+	}
+	return errs
+}
+
+// ConfirmOrder は楽天ペイ受注APIで注文確認待ち(100)の注文を確認します。oList は注文番号で、100件を超える場合は100件ずつに分割して送信します。
+// 注文ごとの結果はレスポンスの MessageModelList に格納されます。確認できなかった注文は OrderErrors で取得することができます。
+// 途中の送信でエラーになった場合は、それまでに送信した注文の結果を格納したレスポンスとエラーを返却します。エラーになった送信以降の注文は確認されていない可能性があります。
+func (a *RMSApi) ConfirmOrder(oList []string) (*ConfirmOrderResponse, error) {
+	return a.ConfirmOrderContext(context.Background(), oList)
+}
+
+// ConfirmOrderContext は ConfirmOrder にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) ConfirmOrderContext(ctx context.Context, oList []string) (*ConfirmOrderResponse, error) {
+	if !a.initialized() {
+		return nil, ErrNotInitialized
+	}
+	result := ConfirmOrderResponse{}
+	for _, chunk := range chunkStrings(oList, CONFIRM_ORDER_MAX_ORDERS) {
+		status, byteArray, err := a.postJSON(ctx, CONFIRM_ORDER_URL, ConfirmOrderRequest{OrderNumberList: chunk})
+		if err != nil {
+			return &result, err
+		}
+
+		r := ConfirmOrderResponse{}
+		err = json.Unmarshal(byteArray, &r)
+		if err != nil && isSuccessStatus(status) {
+			return &result, err
+		}
+		if len(r.MessageModelList) == 0 {
+			return &result, newAPIError(status, byteArray, nil)
+		}
+		result.MessageModelList = append(result.MessageModelList, r.MessageModelList...)
+	}
+	return &result, nil
+}
+
+// OrderErrors はレスポンスのメッセージモデルリストのうち、確認できなかった注文のエラーを OrderError として返却します。
+func (r *ConfirmOrderResponse) OrderErrors() []*OrderError {
+	errs := []*OrderError{}
+	for _, m := range r.MessageModelList {
+		if m.MessageType == "ERROR" {
+			errs = append(errs, &OrderError{m.CommonMessageModelResponse, m.OrderNumber})
+		}
+	}
+	return errs
+}
+
+// NewCancelOrderCondition は取得した注文情報 o からキャンセルの内容を生成します。reason はキャンセル理由、restore は在庫連動区分です。
+// 注文がキャンセルできるステータス(注文確認待ち、発送待ち、変更確定待ち)でない場合や、キャンセル理由が不正な場合は ValidationError を返却します。
+func NewCancelOrderCondition(o *GetOrderOrderModel, reason CancelReason, restore InventoryRestoreType) (*CancelOrderCondition, error) {
+	if !cancellableOrderProgress[o.OrderProgress] {
+		return nil, &ValidationError{Field: "orderProgress", Message: fmt.Sprintf("ステータス%dの注文はキャンセルできません。", o.OrderProgress)}
+	}
+	cond := &CancelOrderCondition{OrderNumber: o.OrderNumber, InventoryRestoreType: restore, ChangeReasonDetailApply: reason}
+	if err := cond.validate(); err != nil {
+		return nil, err
+	}
+	return cond, nil
+}
+
+func (c *CancelOrderCondition) validate() error {
+	switch c.ChangeReasonDetailApply {
+	case CANCEL_REASON_CANCEL, CANCEL_REASON_RETURNED, CANCEL_REASON_LONG_ABSENCE, CANCEL_REASON_UNPAID, CANCEL_REASON_COD_REFUSED, CANCEL_REASON_CUSTOMER_OTHER,
+		CANCEL_REASON_OUT_OF_STOCK, CANCEL_REASON_SHOP_OTHER, CANCEL_REASON_SHIPPING_DELAY, CANCEL_REASON_CAUTION, CANCEL_REASON_RETURNED_DEFECTED:
+	default:
+		return &ValidationError{Field: "changeReasonDetailApply", Message: fmt.Sprintf("キャンセル理由%dは指定できません。", c.ChangeReasonDetailApply)}
+	}
+	if c.InventoryRestoreType != INVENTORY_RESTORE_TYPE_RESTORE && c.InventoryRestoreType != INVENTORY_RESTORE_TYPE_NO_RESTORE {
+		return &ValidationError{Field: "inventoryRestoreType", Message: fmt.Sprintf("在庫連動区分%dは指定できません。", c.InventoryRestoreType)}
+	}
+	return nil
+}
+
+// CancelOrder は楽天ペイ受注APIで注文をキャンセルします。cond はキャンセルの内容です。取得した注文情報からキャンセルする場合は NewCancelOrderCondition で生成してください。
+// キャンセルできなかった場合は注文番号を含む OrderError を持った APIError を返却します。
+func (a *RMSApi) CancelOrder(cond *CancelOrderCondition) (*CancelOrderResponse, error) {
+	return a.CancelOrderContext(context.Background(), cond)
+}
+
+// CancelOrderContext は CancelOrder にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) CancelOrderContext(ctx context.Context, cond *CancelOrderCondition) (*CancelOrderResponse, error) {
+	if !a.initialized() {
+		return nil, ErrNotInitialized
+	}
+	if err := cond.validate(); err != nil {
+		return nil, err
+	}
+	status, byteArray, err := a.postJSON(ctx, CANCEL_ORDER_URL, *cond)
+	if err != nil {
+		return nil, err
+	}
+
+	result := CancelOrderResponse{}
+	err = json.Unmarshal(byteArray, &result)
+	if err != nil && isSuccessStatus(status) {
+		return nil, err
+	}
+	list := []CommonMessageModelResponse{}
+	orderErrors := []*OrderError{}
+	for _, m := range result.MessageModelList {
+		list = append(list, m.CommonMessageModelResponse)
+		if m.MessageType != "INFO" {
+			orderErrors = append(orderErrors, &OrderError{m.CommonMessageModelResponse, cond.OrderNumber})
+		}
+	}
+	if !isSuccessStatus(status) || len(list) == 0 || len(orderErrors) > 0 {
+		e := newAPIError(status, byteArray, list)
+		e.OrderErrors = orderErrors
+		return nil, e
+	}
+	return &result, nil
+}
+
+// GetSubStatusList は楽天ペイ受注APIで店舗が作成したサブステータスの一覧を取得します。
+func (a *RMSApi) GetSubStatusList() (*GetSubStatusListResponse, error) {
+	return a.GetSubStatusListContext(context.Background())
+}
+
+// GetSubStatusListContext は GetSubStatusList にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) GetSubStatusListContext(ctx context.Context) (*GetSubStatusListResponse, error) {
+	if !a.initialized() {
+		return nil, ErrNotInitialized
+	}
+	status, byteArray, err := a.postJSON(ctx, GET_SUB_STATUS_LIST_URL, struct{}{})
+	if err != nil {
+		return nil, err
+	}
+
+	result := GetSubStatusListResponse{}
+	err = json.Unmarshal(byteArray, &result)
+	if err != nil && isSuccessStatus(status) {
+		return nil, err
+	}
+	if !isSuccessStatus(status) || len(result.MessageModelList) == 0 {
+		return nil, newAPIError(status, byteArray, result.MessageModelList)
+	}
+	return &result, nil
+}
+
+// FindByName はサブステータス名からサブステータスを検索します。見つからない場合は false を返却します。
+func (r *GetSubStatusListResponse) FindByName(name string) (SubStatus, bool) {
+	for _, ss := range r.SubStatusModelList {
+		if ss.SubStatusName == name {
+			return ss, true
+		}
+	}
+	return SubStatus{}, false
+}
+
+// UpdateOrderSubStatus は楽天ペイ受注APIで注文を subStatusID のサブステータスに移動します。oList は注文番号で、100件を超える場合は100件ずつに分割して送信します。
+// 注文ごとの結果はレスポンスの MessageModelList に格納されます。移動できなかった注文は OrderErrors で取得することができます。
+// 途中の送信でエラーになった場合は、それまでに送信した注文の結果を格納したレスポンスとエラーを返却します。エラーになった送信以降の注文は移動されていない可能性があります。
+func (a *RMSApi) UpdateOrderSubStatus(subStatusID int, oList []string) (*UpdateOrderSubStatusResponse, error) {
+	return a.UpdateOrderSubStatusContext(context.Background(), subStatusID, oList)
+}
+
+// UpdateOrderSubStatusContext は UpdateOrderSubStatus にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) UpdateOrderSubStatusContext(ctx context.Context, subStatusID int, oList []string) (*UpdateOrderSubStatusResponse, error) {
+	if !a.initialized() {
+		return nil, ErrNotInitialized
+	}
+	result := UpdateOrderSubStatusResponse{}
+	for _, chunk := range chunkStrings(oList, UPDATE_ORDER_SUB_STATUS_MAX_ORDERS) {
+		status, byteArray, err := a.postJSON(ctx, UPDATE_ORDER_SUB_STATUS_URL, UpdateOrderSubStatusRequest{SubStatusID: subStatusID, OrderNumberList: chunk})
+		if err != nil {
+			return &result, err
+		}
+
+		r := UpdateOrderSubStatusResponse{}
+		err = json.Unmarshal(byteArray, &r)
+		if err != nil && isSuccessStatus(status) {
+			return &result, err
+		}
+		if len(r.MessageModelList) == 0 {
+			return &result, newAPIError(status, byteArray, nil)
+		}
+		result.MessageModelList = append(result.MessageModelList, r.MessageModelList...)
+	}
+	return &result, nil
+}
+
+// OrderErrors はレスポンスのメッセージモデルリストのうち、移動できなかった注文のエラーを OrderError として返却します。
+func (r *UpdateOrderSubStatusResponse) OrderErrors() []*OrderError {
+	errs := []*OrderError{}
+	for _, m := range r.MessageModelList {
+		if m.MessageType == "ERROR" {
+			errs = append(errs, &OrderError{m.CommonMessageModelResponse, m.OrderNumber})
+		}
+	}
+	return errs
+}
+
+// NewUpdateOrderDeliveryCondition は取得した注文情報から送付先情報の更新の条件を生成します。生成した条件の送付者モデルを編集して UpdateOrderDelivery に渡すことができます。
+func NewUpdateOrderDeliveryCondition(o *GetOrderOrderModel) *UpdateOrderDeliveryCondition {
+	cond := &UpdateOrderDeliveryCondition{OrderNumber: o.OrderNumber, PackageModelList: []UpdateOrderDeliveryPackageModelCondition{}}
+	for _, p := range o.PackageModelList {
+		cond.PackageModelList = append(cond.PackageModelList, UpdateOrderDeliveryPackageModelCondition{
+			BasketID:    p.BasketID,
+			SenderModel: UpdateOrderSenderModelCondition(p.GetOrderSenderModel),
+		})
+	}
+	return cond
+}
+
+// UpdateOrderDelivery は楽天ペイ受注APIで送付先ごとの送付者の住所、氏名等を更新します。cond は変更対象のデータです。
+func (a *RMSApi) UpdateOrderDelivery(cond *UpdateOrderDeliveryCondition) error {
+	return a.UpdateOrderDeliveryContext(context.Background(), cond)
+}
+
+// UpdateOrderDeliveryContext は UpdateOrderDelivery にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) UpdateOrderDeliveryContext(ctx context.Context, cond *UpdateOrderDeliveryCondition) error {
+	if !a.initialized() {
+		return ErrNotInitialized
+	}
+	return a.updateOrder(ctx, UPDATE_ORDER_SENDER_URL, *cond)
+}
+
+// NewUpdateOrderOrdererCondition は取得した注文情報から注文者情報の更新の条件を生成します。生成した条件の注文者モデルを編集して UpdateOrderOrderer に渡すことができます。
+func NewUpdateOrderOrdererCondition(o *GetOrderOrderModel) *UpdateOrderOrdererCondition {
+	m := o.GetOrderOrdererModel
+	return &UpdateOrderOrdererCondition{
+		OrderNumber: o.OrderNumber,
+		OrdererModel: UpdateOrderOrdererModelCondition{
+			ZipCode1:       m.ZipCode1,
+			ZipCode2:       m.ZipCode2,
+			Prefecture:     m.Prefecture,
+			City:           m.City,
+			SubAddress:     m.SubAddress,
+			FamilyName:     m.FamilyName,
+			FirstName:      m.FirstName,
+			FamilyNameKana: m.FamilyNameKana,
+			FirstNameKana:  m.FirstNameKana,
+			PhoneNumber1:   m.PhoneNumber1,
+			PhoneNumber2:   m.PhoneNumber2,
+			PhoneNumber3:   m.PhoneNumber3,
+			Sex:            m.Sex,
+			BirthYear:      m.BirthYear,
+			BirthMonth:     m.BirthMonth,
+			BirthDay:       m.BirthDay,
+		},
+	}
+}
+
+// UpdateOrderOrderer は楽天ペイ受注APIで注文者の住所、氏名等を更新します。cond は変更対象のデータです。
+func (a *RMSApi) UpdateOrderOrderer(cond *UpdateOrderOrdererCondition) error {
+	return a.UpdateOrderOrdererContext(context.Background(), cond)
+}
+
+// UpdateOrderOrdererContext は UpdateOrderOrderer にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) UpdateOrderOrdererContext(ctx context.Context, cond *UpdateOrderOrdererCondition) error {
+	if !a.initialized() {
+		return ErrNotInitialized
+	}
+	return a.updateOrder(ctx, UPDATE_ORDER_ORDERER_URL, *cond)
+}
+
+// UpdateOrderRemarks は楽天ペイ受注APIで備考欄のコメントを更新します。cond は変更対象のデータです。
+func (a *RMSApi) UpdateOrderRemarks(cond *UpdateOrderRemarksCondition) error {
+	return a.UpdateOrderRemarksContext(context.Background(), cond)
+}
+
+// UpdateOrderRemarksContext は UpdateOrderRemarks にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) UpdateOrderRemarksContext(ctx context.Context, cond *UpdateOrderRemarksCondition) error {
+	if !a.initialized() {
+		return ErrNotInitialized
+	}
+	return a.updateOrder(ctx, UPDATE_ORDER_REMARKS_URL, *cond)
+}
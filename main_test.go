@@ -1,42 +1,81 @@
-package rms
+package rms_test
 
 import (
-	"os"
-	"strconv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
+
+	rms "github.com/hayabusa-systems/rms-go-sdk"
+	"github.com/hayabusa-systems/rms-go-sdk/rmstest"
+)
+
+const (
+	testOrderNumber      = "000000-20200101-0000000001"
+	testBasketID         = 10
+	testShippingDetailID = 100
 )
 
+// newTestServer は注文を3件登録した擬似的なRMSのサーバを起動します。1件目の注文のみ支払い方法が代金引換です。
+func newTestServer() *rmstest.Server {
+	s := rmstest.NewServer("hoge", "fuga")
+	now := time.Now()
+	for i, n := range []string{testOrderNumber, "000000-20200101-0000000002", "000000-20200101-0000000003"} {
+		settlement := "クレジットカード"
+		if i == 0 {
+			settlement = "代金引換"
+		}
+		fixed := rms.JsonTime{Time: now.Add(-time.Duration(i+1) * time.Hour)}
+		s.AddOrder(rms.GetOrderOrderModel{
+			OrderNumber:             n,
+			OrderProgress:           100 + 200*(i%2),
+			OrderDatetime:           fixed,
+			OrderFixDatetime:        &fixed,
+			GetOrderSettlementModel: rms.GetOrderSettlementModel{SettlementMethod: settlement},
+			PackageModelList: []rms.GetOrderPackageModel{
+				{BasketID: testBasketID + i, ShippingModelList: []rms.GetOrderShippingModel{{ShippingDetailID: testShippingDetailID + i}}},
+			},
+		})
+	}
+	return s
+}
+
 func TestSearchOrder_初期化なし(t *testing.T) {
-	a := RMSApi{}
+	a := rms.RMSApi{}
 	_, err := a.SearchOrder(3, time.Now().AddDate(0, 0, -7), time.Now().AddDate(0, 0, 1), nil)
 	if err == nil {
 		t.Error("このテストはエラーを発生させるテストですが、エラーは出ませんでした。")
 		t.FailNow()
 	}
-	if err != nil && err.Error() != "Uninitialized" {
-		t.Errorf("expected: Uninitialized, actual: %s", err.Error())
+	if !errors.Is(err, rms.ErrNotInitialized) {
+		t.Errorf("expected: %v, actual: %v", rms.ErrNotInitialized, err)
 		t.FailNow()
 	}
 }
 
 func TestSearchOrder_認証失敗(t *testing.T) {
-	a := RMSApi{}
-	a.Initialize("hoge", "fuga")
+	s := newTestServer()
+	defer s.Close()
+	a := rms.NewRMSApi("hoge", "piyo", rms.WithBaseURL(s.URL))
 	_, err := a.SearchOrder(3, time.Now().AddDate(0, 0, -7), time.Now().AddDate(0, 0, 1), nil)
 	if err == nil {
 		t.Error("このテストはエラーを発生させるテストですが、エラーは出ませんでした。")
 		t.FailNow()
 	}
-	if err != nil && err.Error() != "Uninitialized" {
-		t.Errorf("expected: Uninitialized, actual: %s", err.Error())
+	apiErr := &rms.APIError{}
+	if !errors.As(err, &apiErr) {
+		t.Errorf("expected: *rms.APIError, actual: %T", err)
 		t.FailNow()
 	}
 }
 
 func TestSearchOrder_引数なし(t *testing.T) {
-	a := RMSApi{}
-	a.Initialize(os.Getenv("SERVICE_SECRET"), os.Getenv("LICENSE_KEY"))
+	s := newTestServer()
+	defer s.Close()
+	a := s.Client()
 	r, err := a.SearchOrder(3, time.Now().AddDate(0, 0, -7), time.Now().AddDate(0, 0, 1), nil)
 	if err != nil {
 		t.Errorf("Happend undefined error: %v", err)
@@ -48,9 +87,10 @@ func TestSearchOrder_引数なし(t *testing.T) {
 }
 
 func TestSearchOrder_ステータス指定の検索(t *testing.T) {
-	a := RMSApi{}
-	a.Initialize(os.Getenv("SERVICE_SECRET"), os.Getenv("LICENSE_KEY"))
-	cond := SearchOrderCondition{}
+	s := newTestServer()
+	defer s.Close()
+	a := s.Client()
+	cond := rms.SearchOrderCondition{}
 	cond.OrderProgressList = append(cond.OrderProgressList, 100)
 	r, err := a.SearchOrder(3, time.Now().AddDate(0, 0, -7), time.Now().AddDate(0, 0, 1), &cond)
 	if err != nil {
@@ -63,9 +103,10 @@ func TestSearchOrder_ステータス指定の検索(t *testing.T) {
 }
 
 func TestSearchOrder_データ数を指定して検索(t *testing.T) {
-	a := RMSApi{}
-	a.Initialize(os.Getenv("SERVICE_SECRET"), os.Getenv("LICENSE_KEY"))
-	cond := SearchOrderCondition{}
+	s := newTestServer()
+	defer s.Close()
+	a := s.Client()
+	cond := rms.SearchOrderCondition{}
 	cond.RequestRecordsAmount = 2
 	cond.RequestPage = 1
 
@@ -81,10 +122,11 @@ func TestSearchOrder_データ数を指定して検索(t *testing.T) {
 }
 
 func TestSearchOrder_containsArrayの含まれるテスト(t *testing.T) {
-	a := RMSApi{}
-	a.Initialize(os.Getenv("SERVICE_SECRET"), os.Getenv("LICENSE_KEY"))
-	cond := SearchOrderCondition{}
-	cond.SettlementMethod = 1
+	s := newTestServer()
+	defer s.Close()
+	a := s.Client()
+	cond := rms.SearchOrderCondition{}
+	cond.SettlementMethod = 2
 
 	r, err := a.SearchOrder(3, time.Now().AddDate(0, 0, -30), time.Now().AddDate(0, 0, 1), &cond)
 	if err != nil {
@@ -106,9 +148,10 @@ func TestSearchOrder_containsArrayの含まれるテスト(t *testing.T) {
 }
 
 func TestSearchOrder_containsArrayの含まれないテスト(t *testing.T) {
-	a := RMSApi{}
-	a.Initialize(os.Getenv("SERVICE_SECRET"), os.Getenv("LICENSE_KEY"))
-	cond := SearchOrderCondition{}
+	s := newTestServer()
+	defer s.Close()
+	a := s.Client()
+	cond := rms.SearchOrderCondition{}
 	cond.SettlementMethod = -1
 
 	r, err := a.SearchOrder(3, time.Now().AddDate(0, 0, -30), time.Now().AddDate(0, 0, 1), &cond)
@@ -122,36 +165,77 @@ func TestSearchOrder_containsArrayの含まれないテスト(t *testing.T) {
 	}
 }
 
+func TestSearchOrder_販売種別と支払い方法の送信(t *testing.T) {
+	var body map[string]interface{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"MessageModelList":[{"messageType":"INFO","messageCode":"ORDER_EXT_API_SEARCH_ORDER_INFO_101"}],"orderNumberList":[]}`))
+	}))
+	defer ts.Close()
+
+	a := rms.NewRMSApi("hoge", "fuga", rms.WithBaseURL(ts.URL))
+	cond := rms.SearchOrderCondition{}
+	cond.OrderTypeList = []int{1, 2, 4}
+	cond.SettlementMethod = 2
+	if _, err := a.SearchOrder(3, time.Now().AddDate(0, 0, -30), time.Now(), &cond); err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	if v := fmt.Sprint(body["orderTypeList"]); v != "[1 4]" {
+		t.Errorf("expected: [1 4], actual: %s", v)
+	}
+	if v := fmt.Sprint(body["settlementMethod"]); v != "2" {
+		t.Errorf("expected: 2, actual: %s", v)
+	}
+
+	body = nil
+	cond = rms.SearchOrderCondition{}
+	cond.OrderTypeList = []int{2}
+	cond.SettlementMethod = -1
+	if _, err := a.SearchOrder(3, time.Now().AddDate(0, 0, -30), time.Now(), &cond); err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	for _, k := range []string{"orderTypeList", "settlementMethod"} {
+		if v, ok := body[k]; ok {
+			t.Errorf("expected: %s is omitted, actual: %v", k, v)
+		}
+	}
+}
+
 func TestGetOrder_初期化なし(t *testing.T) {
-	a := RMSApi{}
+	a := rms.RMSApi{}
 	_, err := a.GetOrder([]string{}, 3)
 	if err == nil {
 		t.Error("このテストはエラーを発生させるテストですが、エラーは出ませんでした。")
 		t.FailNow()
 	}
-	if err != nil && err.Error() != "Uninitialized" {
-		t.Errorf("expected: Uninitialized, actual: %s", err.Error())
+	if !errors.Is(err, rms.ErrNotInitialized) {
+		t.Errorf("expected: %v, actual: %v", rms.ErrNotInitialized, err)
 		t.FailNow()
 	}
 }
 
 func TestGetOrder_認証失敗(t *testing.T) {
-	a := RMSApi{}
-	a.Initialize("hoge", "fuga")
+	s := newTestServer()
+	defer s.Close()
+	a := rms.NewRMSApi("hoge", "piyo", rms.WithBaseURL(s.URL))
 	_, err := a.GetOrder([]string{}, 3)
 	if err == nil {
 		t.Error("このテストはエラーを発生させるテストですが、エラーは出ませんでした。")
 		t.FailNow()
 	}
-	if err != nil && err.Error() != "Uninitialized" {
-		t.Errorf("expected: Uninitialized, actual: %s", err.Error())
+	apiErr := &rms.APIError{}
+	if !errors.As(err, &apiErr) {
+		t.Errorf("expected: *rms.APIError, actual: %T", err)
 		t.FailNow()
 	}
 }
 
 func TestGetOrder_引数なし(t *testing.T) {
-	a := RMSApi{}
-	a.Initialize(os.Getenv("SERVICE_SECRET"), os.Getenv("LICENSE_KEY"))
+	s := newTestServer()
+	defer s.Close()
+	a := s.Client()
 	r, err := a.GetOrder([]string{}, 3)
 	if err != nil {
 		t.Errorf("Happend undefined error: %v", err)
@@ -163,9 +247,10 @@ func TestGetOrder_引数なし(t *testing.T) {
 }
 
 func TestGetOrder_注文番号指定(t *testing.T) {
-	a := RMSApi{}
-	a.Initialize(os.Getenv("SERVICE_SECRET"), os.Getenv("LICENSE_KEY"))
-	r, err := a.GetOrder([]string{os.Getenv("ORDER_NUMBER")}, 3)
+	s := newTestServer()
+	defer s.Close()
+	a := s.Client()
+	r, err := a.GetOrder([]string{testOrderNumber}, 3)
 	if err != nil {
 		t.Errorf("Happend undefined error: %v", err)
 		t.FailNow()
@@ -178,17 +263,18 @@ func TestGetOrder_注文番号指定(t *testing.T) {
 
 func TestUpdateOrderMemo_データ更新1(t *testing.T) {
 	dc := 1
-	dd := JsonDate{time.Now()}
+	dd := rms.JsonDate{time.Now()}
 	st := 1
 	m := "hogefuga"
 	o := "hoge"
 	mps := "hoge"
 
-	a := RMSApi{}
-	a.Initialize(os.Getenv("SERVICE_SECRET"), os.Getenv("LICENSE_KEY"))
+	s := newTestServer()
+	defer s.Close()
+	a := s.Client()
 
-	c := UpdateOrderMemoCondition{}
-	c.OrderNumber = os.Getenv("ORDER_NUMBER")
+	c := rms.UpdateOrderMemoCondition{}
+	c.OrderNumber = testOrderNumber
 	c.DeliveryClass = &dc
 	c.DeliveryDate = &dd
 	c.ShippingTerm = &st
@@ -201,7 +287,7 @@ func TestUpdateOrderMemo_データ更新1(t *testing.T) {
 		t.FailNow()
 	}
 
-	r, err := a.GetOrder([]string{os.Getenv("ORDER_NUMBER")}, 3)
+	r, err := a.GetOrder([]string{testOrderNumber}, 3)
 	if err != nil {
 		t.Errorf("Happend undefined error: %v", err)
 		t.FailNow()
@@ -233,17 +319,18 @@ func TestUpdateOrderMemo_データ更新1(t *testing.T) {
 
 func TestUpdateOrderMemo_データ更新2(t *testing.T) {
 	dc := 0
-	dd := JsonDate{time.Now()}
+	dd := rms.JsonDate{time.Now()}
 	st := 0
 	m := ""
 	o := ""
 	mps := ""
 
-	a := RMSApi{}
-	a.Initialize(os.Getenv("SERVICE_SECRET"), os.Getenv("LICENSE_KEY"))
+	s := newTestServer()
+	defer s.Close()
+	a := s.Client()
 
-	c := UpdateOrderMemoCondition{}
-	c.OrderNumber = os.Getenv("ORDER_NUMBER")
+	c := rms.UpdateOrderMemoCondition{}
+	c.OrderNumber = testOrderNumber
 	c.DeliveryClass = &dc
 	c.DeliveryDate = &dd
 	c.ShippingTerm = &st
@@ -256,7 +343,7 @@ func TestUpdateOrderMemo_データ更新2(t *testing.T) {
 		t.FailNow()
 	}
 
-	r, err := a.GetOrder([]string{os.Getenv("ORDER_NUMBER")}, 3)
+	r, err := a.GetOrder([]string{testOrderNumber}, 3)
 	if err != nil {
 		t.Errorf("Happend undefined error: %v", err)
 		t.FailNow()
@@ -287,20 +374,21 @@ func TestUpdateOrderMemo_データ更新2(t *testing.T) {
 }
 
 func TestUpdateOrderShipping_データ更新1(t *testing.T) {
-	sdid, _ := strconv.Atoi(os.Getenv("SHIPPINGDETAILID"))
+	sdid := testShippingDetailID
 	dc := "1001"
 	sn := "1000"
-	sd := JsonDate{time.Now()}
+	sd := rms.JsonDate{time.Now()}
 	sdf := 0
 
-	a := RMSApi{}
-	a.Initialize(os.Getenv("SERVICE_SECRET"), os.Getenv("LICENSE_KEY"))
+	s := newTestServer()
+	defer s.Close()
+	a := s.Client()
 
-	c := UpdateOrderShippingCondition{}
-	c.OrderNumber = os.Getenv("ORDER_NUMBER")
-	smCond := UpdateOrderShippingBasketidModelCondition{}
-	smCond.BasketID, _ = strconv.Atoi(os.Getenv("BASKETID"))
-	ssmCond := UpdateOrderShippingShippingModelCondition{}
+	c := rms.UpdateOrderShippingCondition{}
+	c.OrderNumber = testOrderNumber
+	smCond := rms.UpdateOrderShippingBasketidModelCondition{}
+	smCond.BasketID = testBasketID
+	ssmCond := rms.UpdateOrderShippingShippingModelCondition{}
 	ssmCond.ShippingDetailID = &sdid
 	ssmCond.DeliveryCompany = &dc
 	ssmCond.ShippingNumber = &sn
@@ -315,7 +403,7 @@ func TestUpdateOrderShipping_データ更新1(t *testing.T) {
 		t.FailNow()
 	}
 
-	r, err := a.GetOrder([]string{os.Getenv("ORDER_NUMBER")}, 3)
+	r, err := a.GetOrder([]string{testOrderNumber}, 3)
 	if err != nil {
 		t.Errorf("Happend undefined error: %v", err)
 		t.FailNow()
@@ -337,20 +425,21 @@ func TestUpdateOrderShipping_データ更新1(t *testing.T) {
 }
 
 func TestUpdateOrderShipping_データ更新2(t *testing.T) {
-	sdid, _ := strconv.Atoi(os.Getenv("SHIPPINGDETAILID"))
+	sdid := testShippingDetailID
 	dc := "1000"
 	sn := ""
-	sd := JsonDate{time.Now()}
+	sd := rms.JsonDate{time.Now()}
 	sdf := 0
 
-	a := RMSApi{}
-	a.Initialize(os.Getenv("SERVICE_SECRET"), os.Getenv("LICENSE_KEY"))
+	s := newTestServer()
+	defer s.Close()
+	a := s.Client()
 
-	c := UpdateOrderShippingCondition{}
-	c.OrderNumber = os.Getenv("ORDER_NUMBER")
-	smCond := UpdateOrderShippingBasketidModelCondition{}
-	smCond.BasketID, _ = strconv.Atoi(os.Getenv("BASKETID"))
-	ssmCond := UpdateOrderShippingShippingModelCondition{}
+	c := rms.UpdateOrderShippingCondition{}
+	c.OrderNumber = testOrderNumber
+	smCond := rms.UpdateOrderShippingBasketidModelCondition{}
+	smCond.BasketID = testBasketID
+	ssmCond := rms.UpdateOrderShippingShippingModelCondition{}
 	ssmCond.ShippingDetailID = &sdid
 	ssmCond.DeliveryCompany = &dc
 	ssmCond.ShippingNumber = &sn
@@ -365,7 +454,7 @@ func TestUpdateOrderShipping_データ更新2(t *testing.T) {
 		t.FailNow()
 	}
 
-	r, err := a.GetOrder([]string{os.Getenv("ORDER_NUMBER")}, 3)
+	r, err := a.GetOrder([]string{testOrderNumber}, 3)
 	if err != nil {
 		t.Errorf("Happend undefined error: %v", err)
 		t.FailNow()
@@ -385,3 +474,271 @@ func TestUpdateOrderShipping_データ更新2(t *testing.T) {
 		t.Errorf("Happend error expected: %s, acctual: %s", sn, *r.OrderModelList[0].PackageModelList[0].ShippingModelList[0].ShippingNumber)
 	}
 }
+
+func TestConfirmOrder_注文確認(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+
+	a := s.Client()
+	r, err := a.ConfirmOrder([]string{testOrderNumber, "000000-20200101-0000000002"})
+	if err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	if len(r.MessageModelList) != 2 || r.MessageModelList[0].MessageType != "INFO" {
+		t.Errorf("expected: INFO, actual: %v", r.MessageModelList)
+	}
+	if oe := r.OrderErrors(); len(oe) != 1 || oe[0].OrderNumber != "000000-20200101-0000000002" {
+		t.Errorf("expected: 000000-20200101-0000000002, actual: %v", oe)
+	}
+	if o, _ := s.Order(testOrderNumber); o.OrderProgress != 300 || o.ShopOrderConfirmDatetime == nil {
+		t.Errorf("expected: 300, actual: %d", o.OrderProgress)
+	}
+}
+
+func TestCancelOrder_在庫を戻してキャンセル(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+
+	a := s.Client()
+	r, err := a.GetOrder([]string{testOrderNumber}, 4)
+	if err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	cond, err := rms.NewCancelOrderCondition(&r.OrderModelList[0], rms.CANCEL_REASON_OUT_OF_STOCK, rms.INVENTORY_RESTORE_TYPE_RESTORE)
+	if err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	if _, err := a.CancelOrder(cond); err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	o, _ := s.Order(testOrderNumber)
+	if o.OrderProgress != 900 || *o.ChangeReasonModelList[0].ChangeReasonDetail != int(rms.CANCEL_REASON_OUT_OF_STOCK) {
+		t.Errorf("expected: 900, actual: %d", o.OrderProgress)
+	}
+
+	_, err = rms.NewCancelOrderCondition(&o, rms.CANCEL_REASON_OUT_OF_STOCK, rms.INVENTORY_RESTORE_TYPE_RESTORE)
+	ve := &rms.ValidationError{}
+	if !errors.As(err, &ve) || ve.Field != "orderProgress" {
+		t.Errorf("expected: *rms.ValidationError, actual: %v", err)
+	}
+}
+
+func TestConfirmOrder_途中で失敗(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests > 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		req := rms.ConfirmOrderRequest{}
+		json.NewDecoder(r.Body).Decode(&req)
+		res := rms.ConfirmOrderResponse{}
+		for _, n := range req.OrderNumberList {
+			res.MessageModelList = append(res.MessageModelList, rms.ConfirmOrderMessageModel{CommonMessageModelResponse: rms.CommonMessageModelResponse{MessageType: "INFO", MessageCode: "ORDER_EXT_API_CONFIRM_ORDER_INFO_101"}, OrderNumber: n})
+		}
+		json.NewEncoder(w).Encode(res)
+	}))
+	defer ts.Close()
+
+	oList := []string{}
+	for i := 0; i < rms.CONFIRM_ORDER_MAX_ORDERS+50; i++ {
+		oList = append(oList, fmt.Sprintf("000000-20200101-%010d", i))
+	}
+	a := rms.NewRMSApi("hoge", "fuga", rms.WithBaseURL(ts.URL))
+	r, err := a.ConfirmOrder(oList)
+	apiErr := &rms.APIError{}
+	if !errors.As(err, &apiErr) {
+		t.Errorf("expected: *APIError, actual: %v", err)
+	}
+	if r == nil || len(r.MessageModelList) != rms.CONFIRM_ORDER_MAX_ORDERS {
+		t.Errorf("expected: %d confirmed orders, actual: %v", rms.CONFIRM_ORDER_MAX_ORDERS, r)
+	}
+}
+
+func TestUpdateOrderSubStatus_サブステータス名で更新(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+	s.AddSubStatus(rms.SubStatus{SubStatusID: 1001, SubStatusName: "入金待ち", OrderBy: 1})
+	s.AddSubStatus(rms.SubStatus{SubStatusID: 1002, SubStatusName: "発送準備中", OrderBy: 2})
+
+	a := s.Client()
+	list, err := a.GetSubStatusList()
+	if err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	ss, ok := list.FindByName("発送準備中")
+	if !ok || ss.SubStatusID != 1002 {
+		t.Errorf("expected: 1002, actual: %v", ss)
+		t.FailNow()
+	}
+	r, err := a.UpdateOrderSubStatus(ss.SubStatusID, []string{testOrderNumber, "000000-20200101-9999999999"})
+	if err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	if oe := r.OrderErrors(); len(oe) != 1 || oe[0].OrderNumber != "000000-20200101-9999999999" {
+		t.Errorf("expected: 000000-20200101-9999999999, actual: %v", oe)
+	}
+	if o, _ := s.Order(testOrderNumber); o.SubStatusID == nil || *o.SubStatusID != 1002 {
+		t.Errorf("expected: 1002, actual: %v", o.SubStatusID)
+	}
+}
+
+func TestUpdateOrderSubStatus_途中で失敗(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests > 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		req := rms.UpdateOrderSubStatusRequest{}
+		json.NewDecoder(r.Body).Decode(&req)
+		res := rms.UpdateOrderSubStatusResponse{}
+		for _, n := range req.OrderNumberList {
+			res.MessageModelList = append(res.MessageModelList, rms.UpdateOrderSubStatusMessageModel{CommonMessageModelResponse: rms.CommonMessageModelResponse{MessageType: "INFO", MessageCode: "ORDER_EXT_API_UPDATE_ORDER_SUB_STATUS_INFO_101"}, OrderNumber: n})
+		}
+		json.NewEncoder(w).Encode(res)
+	}))
+	defer ts.Close()
+
+	oList := []string{}
+	for i := 0; i < rms.UPDATE_ORDER_SUB_STATUS_MAX_ORDERS+50; i++ {
+		oList = append(oList, fmt.Sprintf("000000-20200101-%010d", i))
+	}
+	a := rms.NewRMSApi("hoge", "fuga", rms.WithBaseURL(ts.URL))
+	r, err := a.UpdateOrderSubStatus(1001, oList)
+	apiErr := &rms.APIError{}
+	if !errors.As(err, &apiErr) {
+		t.Errorf("expected: *APIError, actual: %v", err)
+	}
+	if r == nil || len(r.MessageModelList) != rms.UPDATE_ORDER_SUB_STATUS_MAX_ORDERS {
+		t.Errorf("expected: %d updated orders, actual: %v", rms.UPDATE_ORDER_SUB_STATUS_MAX_ORDERS, r)
+	}
+}
+
+func TestUpdateOrderDelivery_送付先の更新(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+
+	a := s.Client()
+	r, err := a.GetOrder([]string{testOrderNumber}, 4)
+	if err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	dc := rms.NewUpdateOrderDeliveryCondition(&r.OrderModelList[0])
+	dc.PackageModelList[0].SenderModel.City = "世田谷区"
+	if err := a.UpdateOrderDelivery(dc); err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	if o, _ := s.Order(testOrderNumber); o.PackageModelList[0].City != "世田谷区" {
+		t.Errorf("expected: 世田谷区, actual: %v", o.PackageModelList[0].City)
+	}
+}
+
+func TestUpdateOrderOrderer_注文者の更新(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+
+	a := s.Client()
+	r, err := a.GetOrder([]string{testOrderNumber}, 4)
+	if err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	oc := rms.NewUpdateOrderOrdererCondition(&r.OrderModelList[0])
+	oc.OrdererModel.FamilyName = "楽天"
+	if err := a.UpdateOrderOrderer(oc); err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	if o, _ := s.Order(testOrderNumber); o.FamilyName != "楽天" {
+		t.Errorf("expected: 楽天, actual: %v", o.FamilyName)
+	}
+}
+
+func TestUpdateOrderRemarks_備考欄の更新(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+
+	a := s.Client()
+	if err := a.UpdateOrderRemarks(&rms.UpdateOrderRemarksCondition{OrderNumber: testOrderNumber, Remarks: "hogefuga"}); err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	if o, _ := s.Order(testOrderNumber); o.Remarks == nil || *o.Remarks != "hogefuga" {
+		t.Errorf("expected: hogefuga, actual: %v", o.Remarks)
+	}
+
+	err := a.UpdateOrderRemarks(&rms.UpdateOrderRemarksCondition{OrderNumber: "000000-20200101-9999999999"})
+	oe := &rms.OrderError{}
+	if !errors.As(err, &oe) || oe.OrderNumber != "000000-20200101-9999999999" {
+		t.Errorf("expected: *rms.OrderError, actual: %v", err)
+	}
+}
+
+func TestGetOrderResponse_JSONの変換(t *testing.T) {
+	payload := `{
+  "MessageModelList": [
+    {"messageType": "INFO", "messageCode": "ORDER_EXT_API_GET_ORDER_INFO_101", "message": "受注情報取得に成功しました。", "orderNumber": "502763-20171027-00006701"}
+  ],
+  "OrderModelList": [
+    {
+      "orderNumber": "502763-20171027-00006701",
+      "orderProgress": 300,
+      "orderDatetime": "2017-10-27T10:47:22+0900",
+      "deliveryDate": "2017-11-01",
+      "reserveNumber": "R-0001",
+      "requestPrice": 4800,
+      "couponAllTotalPrice": 500,
+      "couponShopPrice": 300,
+      "WrappingModel1": {"title": 1, "name": "包装紙A", "price": 200, "includeTaxFlag": 1, "deleteWrappingFlag": 0},
+      "WrappingModel2": {"title": 2, "name": "リボンB", "price": 100, "includeTaxFlag": 1, "deleteWrappingFlag": 0},
+      "PackageModelList": [{"basketId": 11223344}]
+    }
+  ]
+}`
+	r := rms.GetOrderResponse{}
+	if err := json.Unmarshal([]byte(payload), &r); err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	if len(r.OrderModelList) != 1 {
+		t.Errorf("expected: 1, actual: %d", len(r.OrderModelList))
+		t.FailNow()
+	}
+	o := r.OrderModelList[0]
+	if o.DeliveryDate == nil || o.DeliveryDate.Format("2006-01-02") != "2017-11-01" {
+		t.Errorf("expected: 2017-11-01, actual: %v", o.DeliveryDate)
+	}
+	if o.ReserveNumber == nil || *o.ReserveNumber != "R-0001" {
+		t.Errorf("expected: R-0001, actual: %v", o.ReserveNumber)
+	}
+	if o.RequestPrice != 4800 || o.CouponAllTotalPrice != 500 {
+		t.Errorf("expected: 4800 500, actual: %d %d", o.RequestPrice, o.CouponAllTotalPrice)
+	}
+	if o.WrappingModel1.Name != "包装紙A" || o.WrappingModel2.Name != "リボンB" {
+		t.Errorf("expected: 包装紙A リボンB, actual: %s %s", o.WrappingModel1.Name, o.WrappingModel2.Name)
+	}
+
+	b, err := json.Marshal(o)
+	if err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	m := map[string]json.RawMessage{}
+	json.Unmarshal(b, &m)
+	for _, k := range []string{"deliveryDate", "reserveNumber", "couponAllTotalPrice", "WrappingModel1", "WrappingModel2"} {
+		if _, ok := m[k]; !ok {
+			t.Errorf("expected: %s, actual: missing", k)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package rms
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// SettlementType は決済処理の種別を表します。
+type SettlementType int
+
+const (
+	// SETTLEMENT_TYPE_AUTHORIZATION はオーソリ(与信)です。
+	SETTLEMENT_TYPE_AUTHORIZATION SettlementType = 1
+
+	// SETTLEMENT_TYPE_AUTHORIZATION_CANCEL はオーソリの取消です。
+	SETTLEMENT_TYPE_AUTHORIZATION_CANCEL SettlementType = 2
+
+	// SETTLEMENT_TYPE_CAPTURE は売上確定(請求)です。
+	SETTLEMENT_TYPE_CAPTURE SettlementType = 3
+
+	// SETTLEMENT_TYPE_REFUND は返金です。
+	SETTLEMENT_TYPE_REFUND SettlementType = 4
+
+	// SETTLEMENT_TYPE_CHARGEBACK はチャージバックです。
+	SETTLEMENT_TYPE_CHARGEBACK SettlementType = 5
+)
+
+// SettlementStatus は決済処理の状態を表します。
+type SettlementStatus int
+
+const (
+	// SETTLEMENT_STATUS_PROCESSING は処理中です。
+	SETTLEMENT_STATUS_PROCESSING SettlementStatus = 1
+
+	// SETTLEMENT_STATUS_SUCCEEDED は処理が成功したことを表します。
+	SETTLEMENT_STATUS_SUCCEEDED SettlementStatus = 2
+
+	// SETTLEMENT_STATUS_FAILED は処理が失敗したことを表します。
+	SETTLEMENT_STATUS_FAILED SettlementStatus = 3
+)
+
+type (
+	/*** getPayment ***/
+
+	// GetPaymentRequest は楽天ペイ受注APIの決済情報の取得のリクエストです。
+	GetPaymentRequest struct {
+		// OrderNumberList は注文番号リストです。最大100件まで指定可能です。
+		OrderNumberList []string `json:"orderNumberList"`
+	}
+
+	// GetPaymentMessageModel は楽天ペイ受注APIの決済情報の取得で得られるメッセージです。
+	GetPaymentMessageModel struct {
+		// CommonMessageModelResponse は結果の情報が含まれます。
+		CommonMessageModelResponse
+
+		// OrderNumber は注文番号です。
+		OrderNumber string `json:"orderNumber"`
+	}
+
+	// GetPaymentSettlementHistoryModel は楽天ペイ受注APIの決済情報の取得で得られる決済処理の履歴です。
+	GetPaymentSettlementHistoryModel struct {
+		// HistoryID は決済履歴IDです。
+		HistoryID int `json:"historyId"`
+
+		// SettlementType は決済処理の種別です。
+		SettlementType SettlementType `json:"settlementType"`
+
+		// SettlementStatus は決済処理の状態です。
+		SettlementStatus SettlementStatus `json:"settlementStatus"`
+
+		// TaxRate は税率です。注文情報の TaxSummaryModelList の TaxRate に対応します。
+		TaxRate float64 `json:"taxRate"`
+
+		// Amount は金額です。
+		Amount int `json:"amount"`
+
+		// ProcessDatetime は処理日時です。
+		ProcessDatetime *JsonTime `json:"processDatetime"`
+
+		// ErrorCode は決済処理が失敗した場合のエラーコードです。
+		ErrorCode *string `json:"errorCode"`
+	}
+
+	// GetPaymentModel は楽天ペイ受注APIの決済情報の取得で得られる注文ごとの決済情報です。
+	GetPaymentModel struct {
+		// OrderNumber は注文番号です。
+		OrderNumber string `json:"orderNumber"`
+
+		// SettlementMethod は支払い方法名です。
+		SettlementMethod string `json:"settlementMethod"`
+
+		// SettlementHistoryModelList は決済処理の履歴です。処理日時の順に並びます。
+		SettlementHistoryModelList []GetPaymentSettlementHistoryModel `json:"SettlementHistoryModelList"`
+
+		// TaxSummaryModelList は税情報モデルリストです。
+		TaxSummaryModelList []GetOrderTaxSummaryModel `json:"TaxSummaryModelList"`
+	}
+
+	// GetPaymentResponse は楽天ペイ受注APIの決済情報の取得で得られるレスポンスです。
+	GetPaymentResponse struct {
+		// MessageModelList はメッセージモデルリストです。
+		MessageModelList []GetPaymentMessageModel `json:"MessageModelList"`
+
+		// PaymentModelList は注文ごとの決済情報です。
+		PaymentModelList []GetPaymentModel `json:"PaymentModelList"`
+	}
+)
+
+// GetPayment は楽天ペイ受注APIで注文ごとの決済情報と決済処理の履歴を取得します。oList は注文番号で、100件を超える場合は100件ずつに分割して取得します。
+// 取得できなかった注文は OrderErrors で取得することができます。
+func (a *RMSApi) GetPayment(oList []string) (*GetPaymentResponse, error) {
+	return a.GetPaymentContext(context.Background(), oList)
+}
+
+// GetPaymentContext は GetPayment にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) GetPaymentContext(ctx context.Context, oList []string) (*GetPaymentResponse, error) {
+	if !a.initialized() {
+		return nil, ErrNotInitialized
+	}
+	result := GetPaymentResponse{}
+	for _, chunk := range chunkStrings(oList, GET_ORDER_MAX_ORDERS) {
+		status, byteArray, err := a.postJSON(ctx, GET_PAYMENT_URL, GetPaymentRequest{chunk})
+		if err != nil {
+			return nil, err
+		}
+
+		r := GetPaymentResponse{}
+		err = json.Unmarshal(byteArray, &r)
+		if err != nil && isSuccessStatus(status) {
+			return nil, err
+		}
+		if !isSuccessStatus(status) || len(r.MessageModelList) == 0 {
+			list := []CommonMessageModelResponse{}
+			for _, m := range r.MessageModelList {
+				list = append(list, m.CommonMessageModelResponse)
+			}
+			e := newAPIError(status, byteArray, list)
+			e.OrderErrors = r.OrderErrors()
+			return nil, e
+		}
+		result.MessageModelList = append(result.MessageModelList, r.MessageModelList...)
+		result.PaymentModelList = append(result.PaymentModelList, r.PaymentModelList...)
+	}
+	return &result, nil
+}
+
+// OrderErrors はレスポンスのメッセージモデルリストのうち、注文番号が含まれるエラーを OrderError として返却します。
+func (r *GetPaymentResponse) OrderErrors() []*OrderError {
+	errs := []*OrderError{}
+	for _, m := range r.MessageModelList {
+		if m.MessageType == "ERROR" && m.OrderNumber != "" {
+			errs = append(errs, &OrderError{m.CommonMessageModelResponse, m.OrderNumber})
+		}
+	}
+	return errs
+}
+
+// Amount は処理が成功した決済処理のうち、種別が t のものの金額の合計を返却します。
+func (p *GetPaymentModel) Amount(t SettlementType) int {
+	sum := 0
+	for _, h := range p.SettlementHistoryModelList {
+		if h.SettlementType == t && h.SettlementStatus == SETTLEMENT_STATUS_SUCCEEDED {
+			sum += h.Amount
+		}
+	}
+	return sum
+}
+
+// AmountByTaxRate は処理が成功した決済処理のうち、種別が t のものの金額を税率ごとに合計して返却します。TaxSummaryModelList の請求金額との照合に使用することができます。
+func (p *GetPaymentModel) AmountByTaxRate(t SettlementType) map[float64]int {
+	amounts := map[float64]int{}
+	for _, h := range p.SettlementHistoryModelList {
+		if h.SettlementType == t && h.SettlementStatus == SETTLEMENT_STATUS_SUCCEEDED {
+			amounts[h.TaxRate] += h.Amount
+		}
+	}
+	return amounts
+}
+
+// NetCapturedAmount は売上確定の金額から、返金とチャージバックの金額を差し引いた金額を返却します。
+func (p *GetPaymentModel) NetCapturedAmount() int {
+	return p.Amount(SETTLEMENT_TYPE_CAPTURE) - p.Amount(SETTLEMENT_TYPE_REFUND) - p.Amount(SETTLEMENT_TYPE_CHARGEBACK)
+}
+
+// UnreconciledTaxRates は税率ごとの請求金額と、売上確定から返金とチャージバックを差し引いた金額が一致しない税率を返却します。
+// 請求金額が未確定(-9999)の税率は対象外です。
+func (p *GetPaymentModel) UnreconciledTaxRates() []float64 {
+	captured := p.AmountByTaxRate(SETTLEMENT_TYPE_CAPTURE)
+	refunded := p.AmountByTaxRate(SETTLEMENT_TYPE_REFUND)
+	chargeback := p.AmountByTaxRate(SETTLEMENT_TYPE_CHARGEBACK)
+	rates := []float64{}
+	for _, ts := range p.TaxSummaryModelList {
+		if ts.ReqPrice == -9999 {
+			continue
+		}
+		if captured[ts.TaxRate]-refunded[ts.TaxRate]-chargeback[ts.TaxRate] != ts.ReqPrice {
+			rates = append(rates, ts.TaxRate)
+		}
+	}
+	return rates
+}
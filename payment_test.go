@@ -0,0 +1,60 @@
+package rms_test
+
+import (
+	"testing"
+
+	rms "github.com/hayabusa-systems/rms-go-sdk"
+)
+
+func TestGetPaymentModel_返金とチャージバックの照合(t *testing.T) {
+	p := rms.GetPaymentModel{
+		SettlementHistoryModelList: []rms.GetPaymentSettlementHistoryModel{
+			{SettlementType: rms.SETTLEMENT_TYPE_CAPTURE, SettlementStatus: rms.SETTLEMENT_STATUS_SUCCEEDED, TaxRate: 0.1, Amount: 2200},
+			{SettlementType: rms.SETTLEMENT_TYPE_CAPTURE, SettlementStatus: rms.SETTLEMENT_STATUS_SUCCEEDED, TaxRate: 0.08, Amount: 1080},
+			{SettlementType: rms.SETTLEMENT_TYPE_REFUND, SettlementStatus: rms.SETTLEMENT_STATUS_SUCCEEDED, TaxRate: 0.1, Amount: 1100},
+			{SettlementType: rms.SETTLEMENT_TYPE_REFUND, SettlementStatus: rms.SETTLEMENT_STATUS_FAILED, TaxRate: 0.08, Amount: 1080},
+			{SettlementType: rms.SETTLEMENT_TYPE_CHARGEBACK, SettlementStatus: rms.SETTLEMENT_STATUS_SUCCEEDED, TaxRate: 0.08, Amount: 1080},
+		},
+		TaxSummaryModelList: []rms.GetOrderTaxSummaryModel{
+			{TaxRate: 0.1, ReqPrice: 1100},
+			{TaxRate: 0.08, ReqPrice: 1080},
+			{TaxRate: 0, ReqPrice: -9999},
+		},
+	}
+	if actual := p.NetCapturedAmount(); actual != 1100 {
+		t.Errorf("expected: 1100, actual: %d", actual)
+	}
+	if actual := p.AmountByTaxRate(rms.SETTLEMENT_TYPE_REFUND); len(actual) != 1 || actual[0.1] != 1100 {
+		t.Errorf("expected: map[0.1:1100], actual: %v", actual)
+	}
+	if actual := p.UnreconciledTaxRates(); len(actual) != 1 || actual[0] != 0.08 {
+		t.Errorf("expected: [0.08], actual: %v", actual)
+	}
+}
+
+func TestGetPayment_決済情報の取得(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+	s.SetPayment(rms.GetPaymentModel{
+		OrderNumber:      testOrderNumber,
+		SettlementMethod: "クレジットカード",
+		SettlementHistoryModelList: []rms.GetPaymentSettlementHistoryModel{
+			{HistoryID: 1, SettlementType: rms.SETTLEMENT_TYPE_AUTHORIZATION, SettlementStatus: rms.SETTLEMENT_STATUS_SUCCEEDED, TaxRate: 0.1, Amount: 1100},
+			{HistoryID: 2, SettlementType: rms.SETTLEMENT_TYPE_CAPTURE, SettlementStatus: rms.SETTLEMENT_STATUS_SUCCEEDED, TaxRate: 0.1, Amount: 1100},
+		},
+		TaxSummaryModelList: []rms.GetOrderTaxSummaryModel{{TaxRate: 0.1, ReqPrice: 1100}},
+	})
+
+	a := s.Client()
+	r, err := a.GetPayment([]string{testOrderNumber, "000000-20200101-0000000002"})
+	if err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	if len(r.PaymentModelList) != 1 || r.PaymentModelList[0].NetCapturedAmount() != 1100 {
+		t.Errorf("expected: 1100, actual: %v", r.PaymentModelList)
+	}
+	if oe := r.OrderErrors(); len(oe) != 1 || oe[0].OrderNumber != "000000-20200101-0000000002" {
+		t.Errorf("expected: 000000-20200101-0000000002, actual: %v", oe)
+	}
+}
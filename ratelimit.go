@@ -0,0 +1,136 @@
+package rms
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EndpointGroup はレート制限を設定する単位となるエンドポイントのグループです。
+type EndpointGroup int
+
+const (
+	ENDPOINT_GROUP_ORDER     EndpointGroup = iota + 1 // 楽天ペイ受注API
+	ENDPOINT_GROUP_SHOP                               // 店舗API
+	ENDPOINT_GROUP_ITEM                               // 商品API
+	ENDPOINT_GROUP_INVENTORY                          // 在庫API
+	ENDPOINT_GROUP_CATEGORY                           // カテゴリAPI
+	ENDPOINT_GROUP_CABINET                            // R-Cabinet API
+	ENDPOINT_GROUP_COUPON                             // クーポンAPI
+	ENDPOINT_GROUP_LICENSE                            // ライセンス管理API
+)
+
+// ErrRateLimited はレート制限により、コンテキストの期限までにリクエストを送信できない場合のエラーです。
+var ErrRateLimited = errors.New("Rate limit exceeded")
+
+// endpointGroupPrefixes はエンドポイントのパスとグループの対応です。
+var endpointGroupPrefixes = map[string]EndpointGroup{
+	"/es/2.0/order/":              ENDPOINT_GROUP_ORDER,
+	"/es/1.0/shop/":               ENDPOINT_GROUP_SHOP,
+	"/es/1.0/item/":               ENDPOINT_GROUP_ITEM,
+	"/es/2.0/items/":              ENDPOINT_GROUP_ITEM,
+	"/es/2.0/inventories/":        ENDPOINT_GROUP_INVENTORY,
+	"/es/1.0/categoryapi/":        ENDPOINT_GROUP_CATEGORY,
+	"/es/1.0/cabinet/":            ENDPOINT_GROUP_CABINET,
+	"/es/1.0/coupon/":             ENDPOINT_GROUP_COUPON,
+	"/es/1.0/license-management/": ENDPOINT_GROUP_LICENSE,
+}
+
+// RateLimiter はトークンバケット方式のレート制限です。複数のgoroutineから同時に使用することができます。
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter は1秒あたり rps 回、最大 burst 回まで連続してリクエストを送信できる RateLimiter を生成します。
+func NewRateLimiter(rps float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:   rps,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// WithRateLimit はエンドポイントのグループごとにレート制限を設定します。1秒あたり rps 回、最大 burst 回まで連続してリクエストを送信できます。
+// 同じRMSApiを使用するgoroutine間でレート制限は共有されます。
+func WithRateLimit(group EndpointGroup, rps float64, burst int) Option {
+	return WithRateLimiter(group, NewRateLimiter(rps, burst))
+}
+
+// WithRateLimiter はエンドポイントのグループに RateLimiter を設定します。同じライセンスキーを使用する複数のRMSApiでレート制限を共有する場合に使用します。
+func WithRateLimiter(group EndpointGroup, l *RateLimiter) Option {
+	return func(a *RMSApi) {
+		if a.rateLimiters == nil {
+			a.rateLimiters = map[EndpointGroup]*RateLimiter{}
+		}
+		a.rateLimiters[group] = l
+	}
+}
+
+// Wait はリクエストを送信できるようになるまで待機します。
+// ctx の期限までに送信できないことが分かっている場合は待機せずに ErrRateLimited を返却します。待機中に ctx がキャンセルされた場合はそのエラーを返却します。
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	l.tokens--
+	var d time.Duration
+	if l.tokens < 0 {
+		if l.rate <= 0 {
+			l.tokens++
+			l.mu.Unlock()
+			return ErrRateLimited
+		}
+		d = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	if deadline, ok := ctx.Deadline(); ok && d > 0 && now.Add(d).After(deadline) {
+		l.tokens++
+		l.mu.Unlock()
+		return ErrRateLimited
+	}
+	l.mu.Unlock()
+
+	if d == 0 {
+		if err := ctx.Err(); err != nil {
+			l.mu.Lock()
+			l.tokens++
+			l.mu.Unlock()
+			return err
+		}
+		return nil
+	}
+	if err := sleep(ctx, d); err != nil {
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// rateLimiter はエンドポイント u に設定された RateLimiter を返却します。設定されていない場合は nil を返却します。
+func (a *RMSApi) rateLimiter(u string) *RateLimiter {
+	if len(a.rateLimiters) == 0 {
+		return nil
+	}
+	path := strings.TrimPrefix(u, BASE_URL)
+	for prefix, group := range endpointGroupPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return a.rateLimiters[group]
+		}
+	}
+	return nil
+}
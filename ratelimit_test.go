@@ -0,0 +1,87 @@
+package rms
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_待機(t *testing.T) {
+	l := NewRateLimiter(20, 1)
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Errorf("Happend undefined error: %v", err)
+			t.FailNow()
+		}
+	}
+	if d := time.Since(start); d < 90*time.Millisecond {
+		t.Errorf("expected: >= 100ms, actual: %v", d)
+	}
+}
+
+func TestRateLimiter_期限切れ(t *testing.T) {
+	l := NewRateLimiter(1, 1)
+	if err := l.Wait(context.Background()); err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("expected: %v, actual: %v", ErrRateLimited, err)
+	}
+}
+
+func TestRateLimiter_キャンセル済み(t *testing.T) {
+	l := NewRateLimiter(1, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for i := 0; i < 3; i++ {
+		if err := l.Wait(ctx); !errors.Is(err, context.Canceled) {
+			t.Errorf("expected: %v, actual: %v", context.Canceled, err)
+		}
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx); err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+	}
+}
+
+func TestWithRateLimit_グループ別(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<result><resultCode>N000</resultCode></result>`))
+	}))
+	defer ts.Close()
+
+	a := NewRMSApi("hoge", "fuga", WithBaseURL(ts.URL), WithRateLimit(ENDPOINT_GROUP_SHOP, 1, 2), WithRateLimit(ENDPOINT_GROUP_ORDER, 1000, 1))
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 3)
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := a.GetShopCalendarContext(ctx, "", -1)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	limited := 0
+	for err := range errs {
+		if errors.Is(err, ErrRateLimited) {
+			limited++
+		}
+	}
+	if limited != 1 {
+		t.Errorf("expected: 1, actual: %d", limited)
+	}
+}
@@ -0,0 +1,144 @@
+package rms
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy はRMSとの通信に失敗した場合の再試行の設定です。
+type RetryPolicy struct {
+	// MaxAttempts は最初の呼び出しを含めた最大試行回数です。1以下の場合は再試行しません。
+	MaxAttempts int
+
+	// BaseDelay は1回目の再試行までの待機時間の基準値です。再試行のたびに2倍になり、その半分から全体の範囲でランダムに待機します。
+	BaseDelay time.Duration
+
+	// MaxDelay は待機時間の上限です。0の場合は上限を設けません。
+	MaxDelay time.Duration
+
+	// RetryWrites は更新系のAPIも再試行の対象にするかどうかです。更新系のAPIは二重に処理される可能性があるため、既定では再試行しません。
+	RetryWrites bool
+
+	// RetryOn は再試行するかどうかを判定する関数です。status はHTTPのステータスコード、body はレスポンスのボディ、err は通信時のエラーです。
+	// 指定しない場合は DefaultRetryOn が使用されます。
+	RetryOn func(status int, body []byte, err error) bool
+}
+
+// DefaultRetryPolicy は WithRetryPolicy を指定しない場合に使用される再試行の設定です。参照系のAPIのみ、最大3回まで試行します。
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    10 * time.Second,
+}
+
+// readOnlyEndpoints はPOSTで呼び出す参照系のエンドポイントです。GETのエンドポイントは常に参照系として扱います。
+var readOnlyEndpoints = map[string]bool{
+	SEARCH_ORDER_URL:        true,
+	GET_ORDER_URL:           true,
+	GET_PAYMENT_URL:         true,
+	GET_SUB_STATUS_LIST_URL: true,
+	GET_RESULT_UPDATE_ORDER_SHIPPING_ASYNC_URL: true,
+	INVENTORIES_BULK_GET_URL:                   true,
+}
+
+// WithRetryPolicy は通信に失敗した場合の再試行の設定を指定します。再試行を行わない場合は MaxAttempts に1を指定してください。
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(a *RMSApi) {
+		a.retryPolicy = &p
+	}
+}
+
+// DefaultRetryOn は既定の再試行の判定です。通信エラー、5xx、429(リクエスト過多)、およびメンテナンス中(S900)の場合に再試行します。
+// コンテキストがキャンセルされた場合は再試行しません。
+func DefaultRetryOn(status int, body []byte, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	if status == http.StatusTooManyRequests || status >= 500 {
+		return true
+	}
+	return isUnderMaintenance(body)
+}
+
+// isUnderMaintenance はXMLのレスポンスにメンテナンス中(S900)の結果コードが含まれているかを判定します。
+func isUnderMaintenance(body []byte) bool {
+	if !bytes.HasPrefix(bytes.TrimSpace(body), []byte("<")) {
+		return false
+	}
+	r := struct {
+		ResultCode        string            `xml:"resultCode"`
+		ResultMessageList ResultMessageList `xml:"resultMessageList"`
+	}{}
+	if xml.Unmarshal(body, &r) != nil {
+		return false
+	}
+	if r.ResultCode == "S900" {
+		return true
+	}
+	for _, m := range r.ResultMessageList.List {
+		if m.Code == "S900" {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *RMSApi) retry() RetryPolicy {
+	if a.retryPolicy == nil {
+		return DefaultRetryPolicy
+	}
+	return *a.retryPolicy
+}
+
+// attempts はエンドポイントごとの最大試行回数を返却します。
+func (p RetryPolicy) attempts(method, u string) int {
+	if p.MaxAttempts <= 1 {
+		return 1
+	}
+	if method == "GET" || readOnlyEndpoints[u] || p.RetryWrites {
+		return p.MaxAttempts
+	}
+	return 1
+}
+
+func (p RetryPolicy) shouldRetry(status int, body []byte, err error) bool {
+	if p.RetryOn != nil {
+		return p.RetryOn(status, body, err)
+	}
+	return DefaultRetryOn(status, body, err)
+}
+
+// delay は n 回目の再試行までの待機時間を返却します。
+func (p RetryPolicy) delay(n int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < n; i++ {
+		d *= 2
+		if p.MaxDelay > 0 && d > p.MaxDelay {
+			break
+		}
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// sleep は d だけ待機します。待機中に ctx がキャンセルされた場合はそのエラーを返却します。
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,80 @@
+package rms
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy_参照系の再試行(t *testing.T) {
+	count := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count++
+		if count < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"MessageModelList":[{"messageType":"INFO","messageCode":"ORDER_EXT_API_SEARCH_ORDER_INFO_101","message":"注文検索に成功しました。"}],"orderNumberList":[]}`))
+	}))
+	defer ts.Close()
+
+	a := NewRMSApi("hoge", "fuga", WithBaseURL(ts.URL), WithRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+	_, err := a.SearchOrder(3, time.Now().AddDate(0, 0, -7), time.Now(), nil)
+	if err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	if count != 3 {
+		t.Errorf("expected: 3, actual: %d", count)
+	}
+}
+
+func TestRetryPolicy_メンテナンス中(t *testing.T) {
+	count := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count++
+		if count == 1 {
+			w.Write([]byte(`<result><resultCode>S900</resultCode><resultMessageList><resultMessage><code>S900</code><message>Under maintenance.</message></resultMessage></resultMessageList></result>`))
+			return
+		}
+		w.Write([]byte(`<result><resultCode>N000</resultCode></result>`))
+	}))
+	defer ts.Close()
+
+	a := NewRMSApi("hoge", "fuga", WithBaseURL(ts.URL), WithRetryPolicy(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}))
+	r, err := a.GetShopCalendar("", -1)
+	if err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	if count != 2 || r.ResultCode != "N000" {
+		t.Errorf("expected: 2 N000, actual: %d %s", count, r.ResultCode)
+	}
+}
+
+func TestRetryPolicy_更新系は再試行しない(t *testing.T) {
+	count := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	a := NewRMSApi("hoge", "fuga", WithBaseURL(ts.URL), WithRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}))
+	err := a.UpdateOrderMemo(&UpdateOrderMemoCondition{OrderNumber: "000000-20200101-0000000000"})
+	if err == nil {
+		t.Error("このテストはエラーを発生させるテストですが、エラーは出ませんでした。")
+		t.FailNow()
+	}
+	if count != 1 {
+		t.Errorf("expected: 1, actual: %d", count)
+	}
+
+	count = 0
+	a = NewRMSApi("hoge", "fuga", WithBaseURL(ts.URL), WithRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, RetryWrites: true}))
+	a.UpdateOrderMemo(&UpdateOrderMemoCondition{OrderNumber: "000000-20200101-0000000000"})
+	if count != 3 {
+		t.Errorf("expected: 3, actual: %d", count)
+	}
+}
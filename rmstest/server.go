@@ -0,0 +1,715 @@
+/*
+このパッケージはRMS WEB SERVICEを使用するコードをテストするための、プロセス内で動作する擬似的なRMSのサーバを提供します。
+
+	s := rmstest.NewServer("serviceSecret", "licenseKey")
+	defer s.Close()
+	s.AddOrder(rms.GetOrderOrderModel{OrderNumber: "000000-20200101-0000000000", OrderProgress: 100})
+	a := s.Client()
+	r, err := a.GetOrder([]string{"000000-20200101-0000000000"}, 4)
+*/
+package rmstest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	rms "github.com/hayabusa-systems/rms-go-sdk"
+)
+
+// Server は擬似的なRMSのサーバです。注文はメモリ上に保持され、更新系のAPIを呼び出すと内容が書き換わります。
+// 現在は楽天ペイ受注APIの searchOrder、getOrder、updateOrderMemo、updateOrderShipping、updateOrderShippingAsync、getResultUpdateOrderShippingAsync、updateOrderSender、updateOrderOrderer、updateOrderRemarks、confirmOrder、cancelOrder、getPayment、getSubStatusList、updateOrderSubStatus と、店舗APIの shopCalendar、shopCalendar/update に対応しています。
+type Server struct {
+	*httptest.Server
+
+	// ServiceSecret はサーバが受け付けるサービスシークレットです。
+	ServiceSecret string
+
+	// LicenseKey はサーバが受け付けるライセンスキーです。
+	LicenseKey string
+
+	mu                   sync.Mutex
+	mux                  *http.ServeMux
+	orders               map[string]*rms.GetOrderOrderModel
+	payments             map[string]rms.GetPaymentModel
+	subStatuses          []rms.SubStatus
+	calendar             rms.ShopCalendar
+	nextShippingDetailID int
+	asyncShipping        map[int]*asyncShippingRequest
+}
+
+// settlementMethods は注文検索で指定する支払い方法と、受注情報の支払い方法名の対応です。
+var settlementMethods = map[int]string{
+	1:  "クレジットカード",
+	2:  "代金引換",
+	3:  "後払い",
+	4:  "ショッピングクレジット／ローン",
+	5:  "オートローン",
+	6:  "リース",
+	7:  "請求書払い",
+	9:  "銀行振込",
+	12: "Apple Pay",
+	13: "セブンイレブン（前払）",
+	14: "ローソン、郵便局ATM等（前払）",
+	16: "Alipay",
+	17: "PayPal",
+	21: "後払い決済",
+}
+
+// asyncShippingRequest は発送情報の一括追加・更新(非同期)で受け付けたリクエストです。
+type asyncShippingRequest struct {
+	polls   int
+	results []rms.UpdateOrderShippingAsyncResultModel
+}
+
+// NewServer は擬似的なRMSのサーバを起動します。ss はサービスシークレット、lk はライセンスキーで、ESA認証のAuthorizationヘッダがこれらと一致しない場合は401を返却します。
+// 使用後は Close で停止してください。
+func NewServer(ss, lk string) *Server {
+	s := &Server{
+		ServiceSecret:        ss,
+		LicenseKey:           lk,
+		mux:                  http.NewServeMux(),
+		orders:               map[string]*rms.GetOrderOrderModel{},
+		nextShippingDetailID: 1,
+		asyncShipping:        map[int]*asyncShippingRequest{},
+		payments:             map[string]rms.GetPaymentModel{},
+	}
+	s.handle("/es/2.0/order/searchOrder/", s.searchOrder)
+	s.handle("/es/2.0/order/getOrder/", s.getOrder)
+	s.handle("/es/2.0/order/updateOrderMemo/", s.updateOrderMemo)
+	s.handle("/es/2.0/order/updateOrderSender/", s.updateOrderSender)
+	s.handle("/es/2.0/order/updateOrderOrderer/", s.updateOrderOrderer)
+	s.handle("/es/2.0/order/updateOrderRemarks/", s.updateOrderRemarks)
+	s.handle("/es/2.0/order/updateOrderShipping/", s.updateOrderShipping)
+	s.handle("/es/2.0/order/updateOrderShippingAsync/", s.updateOrderShippingAsync)
+	s.handle("/es/2.0/order/getResultUpdateOrderShippingAsync/", s.getResultUpdateOrderShippingAsync)
+	s.handle("/es/2.0/order/confirmOrder/", s.confirmOrder)
+	s.handle("/es/2.0/order/cancelOrder/", s.cancelOrder)
+	s.handle("/es/2.0/order/getPayment/", s.getPayment)
+	s.handle("/es/2.0/order/getSubStatusList/", s.getSubStatusList)
+	s.handle("/es/2.0/order/updateOrderSubStatus/", s.updateOrderSubStatus)
+	s.handle("/es/1.0/shop/shopCalendar", s.shopCalendar)
+	s.handle("/es/1.0/shop/shopCalendar/update", s.updateShopCalendar)
+	s.Server = httptest.NewServer(s.mux)
+	return s
+}
+
+// Client はこのサーバに接続する初期化済みの *rms.RMSApi を生成します。opts で追加の設定を行うことができます。
+func (s *Server) Client(opts ...rms.Option) *rms.RMSApi {
+	return rms.NewRMSApi(s.ServiceSecret, s.LicenseKey, append([]rms.Option{rms.WithBaseURL(s.URL)}, opts...)...)
+}
+
+// AddOrder は注文を登録します。同じ注文番号の注文が既に登録されている場合は置き換えます。
+func (s *Server) AddOrder(o rms.GetOrderOrderModel) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.orders[o.OrderNumber] = &o
+	for _, p := range o.PackageModelList {
+		for _, sm := range p.ShippingModelList {
+			if sm.ShippingDetailID >= s.nextShippingDetailID {
+				s.nextShippingDetailID = sm.ShippingDetailID + 1
+			}
+		}
+	}
+}
+
+// Order は登録されている注文を返却します。更新系のAPIを呼び出した結果を確認する場合に使用します。
+func (s *Server) Order(orderNumber string) (rms.GetOrderOrderModel, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o, ok := s.orders[orderNumber]
+	if !ok {
+		return rms.GetOrderOrderModel{}, false
+	}
+	return *o, true
+}
+
+// SetPayment は注文の決済情報を登録します。登録済みの場合は置き換えます。
+func (s *Server) SetPayment(p rms.GetPaymentModel) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.payments[p.OrderNumber] = p
+}
+
+// AddSubStatus はサブステータスを登録します。
+func (s *Server) AddSubStatus(ss rms.SubStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subStatuses = append(s.subStatuses, ss)
+}
+
+// SetShopCalendar は shopCalendar で返却する営業日カレンダーを設定します。shopCalendar/update で更新された場合は置き換わります。
+// shopCalendar では、日付の指定のうちリクエストの fromDate と period の範囲に含まれるもののみを返却します。
+func (s *Server) SetShopCalendar(c rms.ShopCalendar) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calendar = c
+}
+
+// handle は認証を確認したうえで h を呼び出すハンドラを登録します。
+func (s *Server) handle(pattern string, h http.HandlerFunc) {
+	s.mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		auth := "ESA " + base64.StdEncoding.EncodeToString([]byte(s.ServiceSecret+":"+s.LicenseKey))
+		if r.Header.Get("Authorization") != auth {
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"Results":{"errorCode":"ES01-01","message":"Unauthorized"}}`))
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		h(w, r)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func message(t, code, m string) rms.CommonMessageModelResponse {
+	return rms.CommonMessageModelResponse{MessageType: t, MessageCode: code, Message: m}
+}
+
+func (s *Server) searchOrder(w http.ResponseWriter, r *http.Request) {
+	req := rms.SearchOrderReuquest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, rms.SearchOrderResponse{CommonMessageModelResponseList: []rms.CommonMessageModelResponse{message("ERROR", "ORDER_EXT_API_SEARCH_ORDER_ERROR_001", "リクエストが不正です。")}})
+		return
+	}
+	start, end := req.StartDatetime.Value(), req.EndDatetime.Value()
+	if end.Before(start) || end.Sub(start) > 63*24*time.Hour {
+		writeJSON(w, http.StatusBadRequest, rms.SearchOrderResponse{CommonMessageModelResponseList: []rms.CommonMessageModelResponse{message("ERROR", "ORDER_EXT_API_SEARCH_ORDER_ERROR_012", "期間検索終了日時は期間検索開始日時から63日以内を指定してください。")}})
+		return
+	}
+
+	matched := []*rms.GetOrderOrderModel{}
+	for _, o := range s.orders {
+		d := orderDate(o, req.DateType)
+		if d == nil || d.Before(start) || d.After(end) {
+			continue
+		}
+		if len(req.OrderProgressList) > 0 && !containsInt(req.OrderProgressList, o.OrderProgress) {
+			continue
+		}
+		if len(req.SubStatusIDList) > 0 && (o.SubStatusID == nil || !containsInt(req.SubStatusIDList, *o.SubStatusID)) {
+			continue
+		}
+		if len(req.OrderTypeList) > 0 && !containsInt(req.OrderTypeList, o.OrderType) {
+			continue
+		}
+		if req.SettlementMethod != nil && o.GetOrderSettlementModel.SettlementMethod != settlementMethods[*req.SettlementMethod] {
+			continue
+		}
+		matched = append(matched, o)
+	}
+	desc := len(req.SortModelList) > 0 && req.SortModelList[0].SortDirection == 2
+	sort.Slice(matched, func(i, j int) bool {
+		if desc {
+			return matched[i].OrderDatetime.After(matched[j].OrderDatetime.Time)
+		}
+		return matched[i].OrderDatetime.Before(matched[j].OrderDatetime.Time)
+	})
+
+	size, page := req.RequestRecordsAmount, req.RequestPage
+	if size <= 0 {
+		size = 30
+	}
+	if page <= 0 {
+		page = 1
+	}
+	res := rms.SearchOrderResponse{
+		CommonMessageModelResponseList: []rms.CommonMessageModelResponse{message("INFO", "ORDER_EXT_API_SEARCH_ORDER_INFO_101", "注文検索に成功しました。")},
+		OrderNumberList:                []string{},
+	}
+	res.TotalRecordsAmount = len(matched)
+	res.TotalPages = (len(matched) + size - 1) / size
+	res.RequestPage = page
+	for i := (page - 1) * size; i < page*size && i < len(matched); i++ {
+		res.OrderNumberList = append(res.OrderNumberList, matched[i].OrderNumber)
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// orderDate は期間検索種別に対応する注文の日時を返却します。該当する日時がない場合は nil を返却します。
+func orderDate(o *rms.GetOrderOrderModel, dateType int) *time.Time {
+	var t *rms.JsonTime
+	switch dateType {
+	case rms.DATE_TYPE_ORDER_DATE:
+		t = &o.OrderDatetime
+	case rms.DATE_TYPE_ORDER_CONFIRM_DATE:
+		t = o.ShopOrderConfirmDatetime
+	case rms.DATE_TYPE_ORDER_FIX_DATE:
+		t = o.OrderFixDatetime
+	case rms.DATE_TYPE_SHIPPING_DATE:
+		t = o.ShippingInstDatetime
+	case rms.DATE_TYPE_SHIPPING_COMPLETE_REPORT_DATE:
+		t = o.ShippingCompleteReportDatetime
+	}
+	if t == nil {
+		return nil
+	}
+	return &t.Time
+}
+
+func containsInt(a []int, v int) bool {
+	for _, e := range a {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) getOrder(w http.ResponseWriter, r *http.Request) {
+	req := rms.GetOrderRequest{}
+	json.NewDecoder(r.Body).Decode(&req)
+	res := rms.GetOrderResponse{GetOrderMessageModelList: []rms.GetOrderMessageModel{}, OrderModelList: []rms.GetOrderOrderModel{}}
+	if len(req.OrderNumberList) == 0 || len(req.OrderNumberList) > 100 {
+		res.GetOrderMessageModelList = append(res.GetOrderMessageModelList, rms.GetOrderMessageModel{CommonMessageModelResponse: message("ERROR", "ORDER_EXT_API_GET_ORDER_ERROR_001", "注文番号は1件以上100件以下で指定してください。")})
+		writeJSON(w, http.StatusOK, res)
+		return
+	}
+	for _, n := range req.OrderNumberList {
+		o, ok := s.orders[n]
+		if !ok {
+			res.GetOrderMessageModelList = append(res.GetOrderMessageModelList, rms.GetOrderMessageModel{CommonMessageModelResponse: message("ERROR", "ORDER_EXT_API_GET_ORDER_ERROR_005", "注文番号が存在しません。"), OrderNumber: n})
+			continue
+		}
+		res.GetOrderMessageModelList = append(res.GetOrderMessageModelList, rms.GetOrderMessageModel{CommonMessageModelResponse: message("INFO", "ORDER_EXT_API_GET_ORDER_INFO_101", "受注情報取得に成功しました。"), OrderNumber: n})
+		res.OrderModelList = append(res.OrderModelList, *o)
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) updateOrderMemo(w http.ResponseWriter, r *http.Request) {
+	cond := rms.UpdateOrderMemoCondition{}
+	json.NewDecoder(r.Body).Decode(&cond)
+	o, ok := s.orders[cond.OrderNumber]
+	if !ok {
+		writeJSON(w, http.StatusBadRequest, rms.UpdateOrderMemoResponse{MessageModelList: []rms.UpdateOrderMemoMessageModel{{CommonMessageModelResponse: message("ERROR", "ORDER_EXT_API_UPDATE_ORDERMEMO_ERROR_004", "注文番号が存在しません。"), OrderNumber: cond.OrderNumber}}})
+		return
+	}
+	if cond.SubStatusID != nil {
+		o.SubStatusID = cond.SubStatusID
+	}
+	if cond.DeliveryClass != nil {
+		o.GetOrderDeliveryModel.DeliveryClass = cond.DeliveryClass
+	}
+	if cond.DeliveryDate != nil {
+		o.DeliveryDate = cond.DeliveryDate
+	}
+	if cond.ShippingTerm != nil {
+		o.ShippingTerm = cond.ShippingTerm
+	}
+	if cond.Memo != nil {
+		o.Memo = emptyToNil(cond.Memo)
+	}
+	if cond.Operator != nil {
+		o.Operator = emptyToNil(cond.Operator)
+	}
+	if cond.MailPlugSentence != nil {
+		o.MailPlugSentence = emptyToNil(cond.MailPlugSentence)
+	}
+	writeJSON(w, http.StatusOK, rms.UpdateOrderMemoResponse{MessageModelList: []rms.UpdateOrderMemoMessageModel{{CommonMessageModelResponse: message("INFO", "ORDER_EXT_API_UPDATE_ORDERMEMO_INFO_101", "ひとことメモの更新に成功しました。"), OrderNumber: cond.OrderNumber}}})
+}
+
+func (s *Server) updateOrderSender(w http.ResponseWriter, r *http.Request) {
+	cond := rms.UpdateOrderDeliveryCondition{}
+	json.NewDecoder(r.Body).Decode(&cond)
+	o, ok := s.orders[cond.OrderNumber]
+	if !ok {
+		writeUpdateOrderResult(w, http.StatusBadRequest, message("ERROR", "ORDER_EXT_API_UPDATE_ORDERSENDER_ERROR_004", "注文番号が存在しません。"), cond.OrderNumber)
+		return
+	}
+	for _, pc := range cond.PackageModelList {
+		found := false
+		for i := range o.PackageModelList {
+			if o.PackageModelList[i].BasketID == pc.BasketID {
+				o.PackageModelList[i].GetOrderSenderModel = rms.GetOrderSenderModel(pc.SenderModel)
+				found = true
+			}
+		}
+		if !found {
+			writeUpdateOrderResult(w, http.StatusBadRequest, message("ERROR", "ORDER_EXT_API_UPDATE_ORDERSENDER_ERROR_021", "送付先IDが存在しません。"), cond.OrderNumber)
+			return
+		}
+	}
+	writeUpdateOrderResult(w, http.StatusOK, message("INFO", "ORDER_EXT_API_UPDATE_ORDERSENDER_INFO_101", "送付先情報の更新に成功しました。"), cond.OrderNumber)
+}
+
+func (s *Server) updateOrderOrderer(w http.ResponseWriter, r *http.Request) {
+	cond := rms.UpdateOrderOrdererCondition{}
+	json.NewDecoder(r.Body).Decode(&cond)
+	o, ok := s.orders[cond.OrderNumber]
+	if !ok {
+		writeUpdateOrderResult(w, http.StatusBadRequest, message("ERROR", "ORDER_EXT_API_UPDATE_ORDERORDERER_ERROR_004", "注文番号が存在しません。"), cond.OrderNumber)
+		return
+	}
+	m := cond.OrdererModel
+	email := o.GetOrderOrdererModel.EmailAddress
+	o.GetOrderOrdererModel = rms.GetOrderOrdererModel{
+		ZipCode1:       m.ZipCode1,
+		ZipCode2:       m.ZipCode2,
+		Prefecture:     m.Prefecture,
+		City:           m.City,
+		SubAddress:     m.SubAddress,
+		FamilyName:     m.FamilyName,
+		FirstName:      m.FirstName,
+		FamilyNameKana: m.FamilyNameKana,
+		FirstNameKana:  m.FirstNameKana,
+		PhoneNumber1:   m.PhoneNumber1,
+		PhoneNumber2:   m.PhoneNumber2,
+		PhoneNumber3:   m.PhoneNumber3,
+		EmailAddress:   email,
+		Sex:            m.Sex,
+		BirthYear:      m.BirthYear,
+		BirthMonth:     m.BirthMonth,
+		BirthDay:       m.BirthDay,
+	}
+	writeUpdateOrderResult(w, http.StatusOK, message("INFO", "ORDER_EXT_API_UPDATE_ORDERORDERER_INFO_101", "注文者情報の更新に成功しました。"), cond.OrderNumber)
+}
+
+func (s *Server) updateOrderRemarks(w http.ResponseWriter, r *http.Request) {
+	cond := rms.UpdateOrderRemarksCondition{}
+	json.NewDecoder(r.Body).Decode(&cond)
+	o, ok := s.orders[cond.OrderNumber]
+	if !ok {
+		writeUpdateOrderResult(w, http.StatusBadRequest, message("ERROR", "ORDER_EXT_API_UPDATE_ORDERREMARKS_ERROR_004", "注文番号が存在しません。"), cond.OrderNumber)
+		return
+	}
+	o.Remarks = emptyToNil(&cond.Remarks)
+	writeUpdateOrderResult(w, http.StatusOK, message("INFO", "ORDER_EXT_API_UPDATE_ORDERREMARKS_INFO_101", "備考欄の更新に成功しました。"), cond.OrderNumber)
+}
+
+// writeUpdateOrderResult は注文番号ごとの結果を1件含むレスポンスを書き込みます。
+func writeUpdateOrderResult(w http.ResponseWriter, status int, m rms.CommonMessageModelResponse, orderNumber string) {
+	writeJSON(w, status, rms.UpdateOrderMemoResponse{MessageModelList: []rms.UpdateOrderMemoMessageModel{{CommonMessageModelResponse: m, OrderNumber: orderNumber}}})
+}
+
+// emptyToNil はRMSと同様に、空文字で更新された項目を未設定として扱います。
+func emptyToNil(s *string) *string {
+	if s == nil || *s == "" {
+		return nil
+	}
+	return s
+}
+
+func (s *Server) updateOrderShipping(w http.ResponseWriter, r *http.Request) {
+	cond := rms.UpdateOrderShippingCondition{}
+	json.NewDecoder(r.Body).Decode(&cond)
+	status, res := s.applyShipping(cond)
+	writeJSON(w, status, res)
+}
+
+// applyShipping は発送情報の追加・更新を注文に反映し、ステータスコードとレスポンスを返却します。
+func (s *Server) applyShipping(cond rms.UpdateOrderShippingCondition) (int, rms.UpdateOrderShippingResponse) {
+	o, ok := s.orders[cond.OrderNumber]
+	if !ok {
+		return http.StatusBadRequest, rms.UpdateOrderShippingResponse{MessageModelList: []rms.UpdateOrderShippingMessageModel{{CommonMessageModelResponse: message("ERROR", "ORDER_EXT_API_UPDATE_ORDERSHIPPING_ERROR_004", "注文番号が存在しません。")}}}
+	}
+	res := rms.UpdateOrderShippingResponse{}
+	dataNumber := 0
+	for _, b := range cond.BasketidModelList {
+		var p *rms.GetOrderPackageModel
+		for i := range o.PackageModelList {
+			if o.PackageModelList[i].BasketID == b.BasketID {
+				p = &o.PackageModelList[i]
+			}
+		}
+		for _, sm := range b.ShippingModelList {
+			dataNumber++
+			if p == nil {
+				res.MessageModelList = append(res.MessageModelList, rms.UpdateOrderShippingMessageModel{CommonMessageModelResponse: message("ERROR", "ORDER_EXT_API_UPDATE_ORDERSHIPPING_ERROR_006", "送付先IDが存在しません。"), DataNumber: dataNumber})
+				continue
+			}
+			id := s.applyShippingModel(p, sm)
+			if id == 0 {
+				res.MessageModelList = append(res.MessageModelList, rms.UpdateOrderShippingMessageModel{CommonMessageModelResponse: message("ERROR", "ORDER_EXT_API_UPDATE_ORDERSHIPPING_ERROR_007", "発送明細IDが存在しません。"), DataNumber: dataNumber})
+				continue
+			}
+			res.MessageModelList = append(res.MessageModelList, rms.UpdateOrderShippingMessageModel{CommonMessageModelResponse: message("INFO", "ORDER_EXT_API_UPDATE_ORDERSHIPPING_INFO_101", "発送情報の更新に成功しました。"), DataNumber: dataNumber, ShippingDetailID: id})
+		}
+	}
+	for _, m := range res.MessageModelList {
+		if m.MessageType == "ERROR" {
+			return http.StatusBadRequest, res
+		}
+	}
+	return http.StatusOK, res
+}
+
+// updateOrderShippingAsync は受け付けた時点で発送情報を反映し、結果を保持します。結果取得の1回目は処理中を返却し、2回目以降で結果を返却します。
+func (s *Server) updateOrderShippingAsync(w http.ResponseWriter, r *http.Request) {
+	req := rms.UpdateOrderShippingAsyncRequest{}
+	json.NewDecoder(r.Body).Decode(&req)
+	if len(req.OrderShippingModelList) == 0 || len(req.OrderShippingModelList) > rms.UPDATE_ORDER_SHIPPING_ASYNC_MAX_ORDERS {
+		writeJSON(w, http.StatusBadRequest, rms.UpdateOrderShippingAsyncResponse{MessageModelList: []rms.CommonMessageModelResponse{message("ERROR", "ORDER_EXT_API_UPDATE_ORDERSHIPPING_ASYNC_ERROR_001", "注文の件数が不正です。")}})
+		return
+	}
+	ar := &asyncShippingRequest{}
+	offset := 0
+	for _, cond := range req.OrderShippingModelList {
+		n := 0
+		for _, b := range cond.BasketidModelList {
+			n += len(b.ShippingModelList)
+		}
+		_, res := s.applyShipping(cond)
+		if _, ok := s.orders[cond.OrderNumber]; !ok {
+			for i := 1; i <= n; i++ {
+				ar.results = append(ar.results, rms.UpdateOrderShippingAsyncResultModel{CommonMessageModelResponse: res.MessageModelList[0].CommonMessageModelResponse, OrderNumber: cond.OrderNumber, DataNumber: offset + i})
+			}
+		} else {
+			for _, m := range res.MessageModelList {
+				ar.results = append(ar.results, rms.UpdateOrderShippingAsyncResultModel{CommonMessageModelResponse: m.CommonMessageModelResponse, OrderNumber: cond.OrderNumber, DataNumber: offset + m.DataNumber, ShippingDetailID: m.ShippingDetailID})
+			}
+		}
+		offset += n
+	}
+	id := len(s.asyncShipping) + 1
+	s.asyncShipping[id] = ar
+	writeJSON(w, http.StatusOK, rms.UpdateOrderShippingAsyncResponse{MessageModelList: []rms.CommonMessageModelResponse{message("INFO", "ORDER_EXT_API_UPDATE_ORDERSHIPPING_ASYNC_INFO_101", "発送情報の一括更新を受け付けました。")}, RequestID: id})
+}
+
+func (s *Server) getResultUpdateOrderShippingAsync(w http.ResponseWriter, r *http.Request) {
+	req := rms.GetResultUpdateOrderShippingAsyncRequest{}
+	json.NewDecoder(r.Body).Decode(&req)
+	ar, ok := s.asyncShipping[req.RequestID]
+	if !ok {
+		writeJSON(w, http.StatusBadRequest, rms.GetResultUpdateOrderShippingAsyncResponse{MessageModelList: []rms.CommonMessageModelResponse{message("ERROR", "ORDER_EXT_API_GET_RESULT_UPDATE_ORDERSHIPPING_ASYNC_ERROR_002", "リクエストIDが存在しません。")}})
+		return
+	}
+	ar.polls++
+	res := rms.GetResultUpdateOrderShippingAsyncResponse{MessageModelList: []rms.CommonMessageModelResponse{message("INFO", "ORDER_EXT_API_GET_RESULT_UPDATE_ORDERSHIPPING_ASYNC_INFO_101", "結果の取得に成功しました。")}, RequestStatus: rms.ASYNC_REQUEST_STATUS_PROCESSING}
+	if ar.polls > 1 {
+		res.RequestStatus = rms.ASYNC_REQUEST_STATUS_COMPLETED
+		res.ResultModelList = ar.results
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// applyShippingModel は送付先に発送情報を反映し、対象の発送明細IDを返却します。指定された発送明細IDが存在しない場合は0を返却します。
+func (s *Server) applyShippingModel(p *rms.GetOrderPackageModel, sm rms.UpdateOrderShippingShippingModelCondition) int {
+	if sm.ShippingDetailID == nil {
+		id := s.nextShippingDetailID
+		s.nextShippingDetailID++
+		p.ShippingModelList = append(p.ShippingModelList, rms.GetOrderShippingModel{
+			ShippingDetailID: id,
+			ShippingNumber:   emptyToNil(sm.ShippingNumber),
+			DeliveryCompany:  sm.DeliveryCompany,
+			ShippingDate:     sm.ShippingDate,
+		})
+		return id
+	}
+	for i := range p.ShippingModelList {
+		m := &p.ShippingModelList[i]
+		if m.ShippingDetailID != *sm.ShippingDetailID {
+			continue
+		}
+		if sm.ShippingDeleteFlag != nil && *sm.ShippingDeleteFlag == 1 {
+			p.ShippingModelList = append(p.ShippingModelList[:i], p.ShippingModelList[i+1:]...)
+			return *sm.ShippingDetailID
+		}
+		m.ShippingNumber = emptyToNil(sm.ShippingNumber)
+		if sm.DeliveryCompany != nil {
+			m.DeliveryCompany = sm.DeliveryCompany
+		}
+		if sm.ShippingDate != nil {
+			m.ShippingDate = sm.ShippingDate
+		}
+		return m.ShippingDetailID
+	}
+	return 0
+}
+
+func (s *Server) confirmOrder(w http.ResponseWriter, r *http.Request) {
+	req := rms.ConfirmOrderRequest{}
+	json.NewDecoder(r.Body).Decode(&req)
+	res := rms.ConfirmOrderResponse{}
+	for _, n := range req.OrderNumberList {
+		o, ok := s.orders[n]
+		switch {
+		case !ok:
+			res.MessageModelList = append(res.MessageModelList, rms.ConfirmOrderMessageModel{CommonMessageModelResponse: message("ERROR", "ORDER_EXT_API_CONFIRM_ORDER_ERROR_004", "注文番号が存在しません。"), OrderNumber: n})
+		case o.OrderProgress != 100:
+			res.MessageModelList = append(res.MessageModelList, rms.ConfirmOrderMessageModel{CommonMessageModelResponse: message("ERROR", "ORDER_EXT_API_CONFIRM_ORDER_ERROR_005", "注文確認待ちの注文ではありません。"), OrderNumber: n})
+		default:
+			now := rms.JsonTime{Time: time.Now()}
+			o.OrderProgress = 300
+			o.ShopOrderConfirmDatetime = &now
+			res.MessageModelList = append(res.MessageModelList, rms.ConfirmOrderMessageModel{CommonMessageModelResponse: message("INFO", "ORDER_EXT_API_CONFIRM_ORDER_INFO_101", "注文確認に成功しました。"), OrderNumber: n})
+		}
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) cancelOrder(w http.ResponseWriter, r *http.Request) {
+	cond := rms.CancelOrderCondition{}
+	json.NewDecoder(r.Body).Decode(&cond)
+	o, ok := s.orders[cond.OrderNumber]
+	if !ok {
+		writeJSON(w, http.StatusBadRequest, rms.CancelOrderResponse{MessageModelList: []rms.CancelOrderMessageModel{{CommonMessageModelResponse: message("ERROR", "ORDER_EXT_API_CANCEL_ORDER_ERROR_004", "注文番号が存在しません。"), OrderNumber: cond.OrderNumber}}})
+		return
+	}
+	if o.OrderProgress != 100 && o.OrderProgress != 300 && o.OrderProgress != 400 {
+		writeJSON(w, http.StatusBadRequest, rms.CancelOrderResponse{MessageModelList: []rms.CancelOrderMessageModel{{CommonMessageModelResponse: message("ERROR", "ORDER_EXT_API_CANCEL_ORDER_ERROR_005", "キャンセルできないステータスです。"), OrderNumber: cond.OrderNumber}}})
+		return
+	}
+	changeType, reason, detail := 1, 0, int(cond.ChangeReasonDetailApply)
+	if detail <= 6 {
+		reason = 1
+	}
+	now := rms.JsonTime{Time: time.Now()}
+	o.OrderProgress = 900
+	o.ChangeReasonModelList = append(o.ChangeReasonModelList, rms.GetOrderChangeReasonModel{
+		ChangeID:            len(o.ChangeReasonModelList) + 1,
+		ChangeType:          &changeType,
+		ChangeReason:        &reason,
+		ChangeReasonDetail:  &detail,
+		ChangeApplyDatetime: &now,
+		ChangeFixDatetime:   &now,
+	})
+	writeJSON(w, http.StatusOK, rms.CancelOrderResponse{MessageModelList: []rms.CancelOrderMessageModel{{CommonMessageModelResponse: message("INFO", "ORDER_EXT_API_CANCEL_ORDER_INFO_101", "注文キャンセルに成功しました。"), OrderNumber: cond.OrderNumber}}})
+}
+
+func (s *Server) getPayment(w http.ResponseWriter, r *http.Request) {
+	req := rms.GetPaymentRequest{}
+	json.NewDecoder(r.Body).Decode(&req)
+	res := rms.GetPaymentResponse{}
+	for _, n := range req.OrderNumberList {
+		p, ok := s.payments[n]
+		if !ok {
+			res.MessageModelList = append(res.MessageModelList, rms.GetPaymentMessageModel{CommonMessageModelResponse: message("ERROR", "ORDER_EXT_API_GET_PAYMENT_ERROR_004", "注文番号が存在しません。"), OrderNumber: n})
+			continue
+		}
+		res.MessageModelList = append(res.MessageModelList, rms.GetPaymentMessageModel{CommonMessageModelResponse: message("INFO", "ORDER_EXT_API_GET_PAYMENT_INFO_101", "決済情報の取得に成功しました。"), OrderNumber: n})
+		res.PaymentModelList = append(res.PaymentModelList, p)
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) getSubStatusList(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, rms.GetSubStatusListResponse{
+		MessageModelList:   []rms.CommonMessageModelResponse{message("INFO", "ORDER_EXT_API_GET_SUB_STATUS_LIST_INFO_101", "サブステータス一覧取得に成功しました。")},
+		SubStatusModelList: append([]rms.SubStatus{}, s.subStatuses...),
+	})
+}
+
+func (s *Server) updateOrderSubStatus(w http.ResponseWriter, r *http.Request) {
+	req := rms.UpdateOrderSubStatusRequest{}
+	json.NewDecoder(r.Body).Decode(&req)
+	var ss *rms.SubStatus
+	for i := range s.subStatuses {
+		if s.subStatuses[i].SubStatusID == req.SubStatusID {
+			ss = &s.subStatuses[i]
+		}
+	}
+	res := rms.UpdateOrderSubStatusResponse{}
+	for _, n := range req.OrderNumberList {
+		o, ok := s.orders[n]
+		switch {
+		case ss == nil:
+			res.MessageModelList = append(res.MessageModelList, rms.UpdateOrderSubStatusMessageModel{CommonMessageModelResponse: message("ERROR", "ORDER_EXT_API_UPDATE_ORDER_SUB_STATUS_ERROR_003", "サブステータスIDが存在しません。"), OrderNumber: n})
+		case !ok:
+			res.MessageModelList = append(res.MessageModelList, rms.UpdateOrderSubStatusMessageModel{CommonMessageModelResponse: message("ERROR", "ORDER_EXT_API_UPDATE_ORDER_SUB_STATUS_ERROR_004", "注文番号が存在しません。"), OrderNumber: n})
+		default:
+			id, name := ss.SubStatusID, ss.SubStatusName
+			o.SubStatusID = &id
+			o.SubStatusName = &name
+			res.MessageModelList = append(res.MessageModelList, rms.UpdateOrderSubStatusMessageModel{CommonMessageModelResponse: message("INFO", "ORDER_EXT_API_UPDATE_ORDER_SUB_STATUS_INFO_101", "サブステータスの更新に成功しました。"), OrderNumber: n})
+		}
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// shopCalendar はRMSと同様に、fromDate から period 日分の日付のみを返却します。fromDate を指定しない場合は現在の日付から、period を指定しない場合は90日分です。
+func (s *Server) shopCalendar(w http.ResponseWriter, r *http.Request) {
+	res := rms.ShopBizApiResponse{
+		ResultCode:        "N000",
+		ResultMessageList: rms.ResultMessageList{List: []rms.ResultMessage{{Code: "N000", Message: "Succeeded."}}},
+	}
+	status := http.StatusOK
+	from, period, ok := calendarRange(r.URL.Query())
+	if ok {
+		c := s.calendar
+		c.BusinessHoliday.EventDates.EventDate = filterEventDates(c.BusinessHoliday.EventDates.EventDate, from, period)
+		c.ShippingHoliday.EventDates.EventDate = filterEventDates(c.ShippingHoliday.EventDates.EventDate, from, period)
+		c.ShippingOnly.EventDates.EventDate = filterEventDates(c.ShippingOnly.EventDates.EventDate, from, period)
+		res.Result = &rms.ShopCalendarBizModel{Calendar: c}
+	} else {
+		status = http.StatusBadRequest
+		res = rms.ShopBizApiResponse{ResultCode: "C001", ResultMessageList: rms.ResultMessageList{List: []rms.ResultMessage{{Code: "C001", Message: "Request parameter is invalid."}}}}
+	}
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(status)
+	b, _ := xml.Marshal(struct {
+		XMLName xml.Name `xml:"result"`
+		rms.ShopBizApiResponse
+	}{ShopBizApiResponse: res})
+	w.Write([]byte(xml.Header))
+	w.Write(b)
+}
+
+// calendarRange はクエリの fromDate と period から、返却する期間の開始日と日数を返却します。形式が不正な場合は false を返却します。
+func calendarRange(q url.Values) (time.Time, int, bool) {
+	jst := time.FixedZone("Asia/Tokyo", 9*60*60)
+	now := time.Now().In(jst)
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, jst)
+	if v := q.Get("fromDate"); v != "" {
+		t, err := time.ParseInLocation("2006-01-02", v, jst)
+		if err != nil {
+			return time.Time{}, 0, false
+		}
+		from = t
+	}
+	period := 90
+	if v := q.Get("period"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 180 {
+			return time.Time{}, 0, false
+		}
+		period = n
+	}
+	return from, period, true
+}
+
+// filterEventDates は dates のうち from から period 日分に含まれる日付を返却します。
+func filterEventDates(dates []string, from time.Time, period int) []string {
+	start := from.Format(rms.CALENDAR_EVENT_DATE_FORMAT)
+	end := from.AddDate(0, 0, period).Format(rms.CALENDAR_EVENT_DATE_FORMAT)
+	list := []string{}
+	for _, d := range dates {
+		if d >= start && d < end {
+			list = append(list, d)
+		}
+	}
+	return list
+}
+
+func (s *Server) updateShopCalendar(w http.ResponseWriter, r *http.Request) {
+	req := rms.ShopCalendarUpdateRequest{}
+	res := rms.ShopBizApiResponse{ResultCode: "N000", ResultMessageList: rms.ResultMessageList{List: []rms.ResultMessage{{Code: "N000", Message: "Succeeded."}}}}
+	status := http.StatusOK
+	if err := xml.NewDecoder(r.Body).Decode(&req); err != nil {
+		status = http.StatusBadRequest
+		res = rms.ShopBizApiResponse{ResultCode: "C007", ResultMessageList: rms.ResultMessageList{List: []rms.ResultMessage{{Code: "C007", Message: "Request Model is invalid."}}}}
+	} else {
+		s.calendar = req.Calendar
+	}
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(status)
+	b, _ := xml.Marshal(struct {
+		XMLName xml.Name `xml:"result"`
+		rms.ShopBizApiResponse
+	}{ShopBizApiResponse: res})
+	w.Write([]byte(xml.Header))
+	w.Write(b)
+}
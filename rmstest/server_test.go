@@ -0,0 +1,151 @@
+package rmstest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	rms "github.com/hayabusa-systems/rms-go-sdk"
+)
+
+func newTestServer() *Server {
+	s := NewServer("hoge", "fuga")
+	now := time.Now()
+	for i, n := range []string{"000000-20200101-0000000001", "000000-20200101-0000000002", "000000-20200101-0000000003"} {
+		s.AddOrder(rms.GetOrderOrderModel{
+			OrderNumber:   n,
+			OrderProgress: 100 + 200*(i%2),
+			OrderDatetime: rms.JsonTime{Time: now.Add(-time.Duration(i+1) * time.Hour)},
+			PackageModelList: []rms.GetOrderPackageModel{
+				{BasketID: 10 + i, ShippingModelList: []rms.GetOrderShippingModel{{ShippingDetailID: 100 + i}}},
+			},
+		})
+	}
+	return s
+}
+
+func TestServer_認証失敗(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+
+	a := rms.NewRMSApi("hoge", "piyo", rms.WithBaseURL(s.URL))
+	_, err := a.GetOrder([]string{"000000-20200101-0000000001"}, 4)
+	if !errors.Is(err, rms.ErrUnauthorized) {
+		t.Errorf("expected: %v, actual: %v", rms.ErrUnauthorized, err)
+	}
+}
+
+func TestServer_注文検索(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+
+	a := s.Client()
+	cond := rms.SearchOrderCondition{OrderProgressList: []int{100}, RequestRecordsAmount: 1}
+	oList, err := a.SearchOrderAll(context.Background(), rms.DATE_TYPE_ORDER_DATE, time.Now().AddDate(0, 0, -1), time.Now(), &cond)
+	if err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	if len(oList) != 2 || oList[0] != "000000-20200101-0000000003" {
+		t.Errorf("expected: [000000-20200101-0000000003 000000-20200101-0000000001], actual: %v", oList)
+	}
+}
+
+func TestServer_注文情報の取得(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+
+	a := s.Client()
+	r, err := a.GetOrder([]string{"000000-20200101-0000000002", "000000-20200101-9999999999"}, 4)
+	if err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	if len(r.OrderModelList) != 1 || r.OrderModelList[0].OrderProgress != 300 {
+		t.Errorf("expected: 1 order in 300, actual: %v", r.OrderModelList)
+	}
+	if oe := r.OrderErrors(); len(oe) != 1 || oe[0].OrderNumber != "000000-20200101-9999999999" {
+		t.Errorf("expected: 000000-20200101-9999999999, actual: %v", oe)
+	}
+}
+
+func TestServer_ひとことメモの更新(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+
+	a := s.Client()
+	m := "hogefuga"
+	err := a.UpdateOrderMemo(&rms.UpdateOrderMemoCondition{OrderNumber: "000000-20200101-0000000001", Memo: &m})
+	if err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	o, _ := s.Order("000000-20200101-0000000001")
+	if o.Memo == nil || *o.Memo != m {
+		t.Errorf("expected: %s, actual: %v", m, o.Memo)
+	}
+
+	err = a.UpdateOrderMemo(&rms.UpdateOrderMemoCondition{OrderNumber: "000000-20200101-9999999999", Memo: &m})
+	oe := &rms.OrderError{}
+	if !errors.As(err, &oe) || oe.OrderNumber != "000000-20200101-9999999999" {
+		t.Errorf("expected: *rms.OrderError, actual: %v", err)
+	}
+}
+
+func TestServer_発送情報の更新(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+
+	a := s.Client()
+	id := 100
+	dc := "1001"
+	sn := "1234-5678-9012"
+	cond := rms.UpdateOrderShippingCondition{
+		OrderNumber: "000000-20200101-0000000001",
+		BasketidModelList: []rms.UpdateOrderShippingBasketidModelCondition{
+			{BasketID: 10, ShippingModelList: []rms.UpdateOrderShippingShippingModelCondition{
+				{ShippingDetailID: &id, DeliveryCompany: &dc, ShippingNumber: &sn},
+				{DeliveryCompany: &dc, ShippingNumber: &sn},
+			}},
+		},
+	}
+	if err := a.UpdateOrderShipping(&cond); err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	o, _ := s.Order("000000-20200101-0000000001")
+	sm := o.PackageModelList[0].ShippingModelList
+	if len(sm) != 2 || *sm[0].ShippingNumber != sn || sm[1].ShippingDetailID != 103 {
+		t.Errorf("expected: 2 shipping models, actual: %v", sm)
+	}
+}
+
+func TestServer_営業日カレンダー(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+
+	c := rms.ShopCalendar{}
+	c.BusinessHoliday.RegularSchedule.Weekday = []string{"SUN"}
+	c.BusinessHoliday.EventDates.EventDate = []string{"20200430", "20200505", "20200530", "20200531"}
+	s.SetShopCalendar(c)
+
+	a := s.Client()
+	r, err := a.GetShopCalendar("2020-05-01", 30)
+	if err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	if r.Result == nil || len(r.Result.Calendar.BusinessHoliday.EventDates.EventDate) != 2 || r.Result.Calendar.BusinessHoliday.EventDates.EventDate[1] != "20200530" {
+		t.Errorf("expected: [20200505 20200530], actual: %v", r.Result)
+	}
+
+	r, err = a.GetShopCalendar("", -1)
+	if err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	if r.Result == nil || len(r.Result.Calendar.BusinessHoliday.EventDates.EventDate) != 0 || len(r.Result.Calendar.BusinessHoliday.RegularSchedule.Weekday) != 1 {
+		t.Errorf("expected: SUN only, actual: %v", r.Result)
+	}
+}
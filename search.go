@@ -0,0 +1,298 @@
+package rms
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	// SEARCH_ORDER_MAX_DAYS は注文検索で一度に指定できる期間の日数です。
+	SEARCH_ORDER_MAX_DAYS = 63
+
+	// SEARCH_ORDER_MAX_YEARS は注文検索で指定できる開始日時の過去の年数です。
+	SEARCH_ORDER_MAX_YEARS = 2
+)
+
+// OrderNumberIterator は楽天ペイ受注APIの注文検索の結果を、すべてのページにわたって1件ずつ取得するためのイテレータです。
+// 複数のページに同じ注文番号が含まれる場合、2件目以降は読み飛ばします。
+//
+//	it := a.SearchOrderIterator(ctx, DATE_TYPE_ORDER_DATE, start, end, nil)
+//	for it.Next() {
+//		fmt.Println(it.OrderNumber())
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type OrderNumberIterator struct {
+	a             *RMSApi
+	ctx           context.Context
+	dateType      SearchOrderDateType
+	startDatetime time.Time
+	endDatetime   time.Time
+	cond          SearchOrderCondition
+
+	page    int
+	last    bool
+	buf     []string
+	current string
+	seen    map[string]struct{}
+	err     error
+}
+
+// SearchOrderIterator は注文検索の結果をすべてのページにわたって取得する OrderNumberIterator を生成します。引数は SearchOrder と同じですが、cond の RequestPage は無視され、1ページ目から順に取得します。
+// RequestRecordsAmount が指定されていない場合は1ページあたり1,000件ずつ取得します。
+func (a *RMSApi) SearchOrderIterator(ctx context.Context, dateType SearchOrderDateType, startDatetime, endDatetime time.Time, cond *SearchOrderCondition) *OrderNumberIterator {
+	it := &OrderNumberIterator{
+		a:             a,
+		ctx:           ctx,
+		dateType:      dateType,
+		startDatetime: startDatetime,
+		endDatetime:   endDatetime,
+		seen:          map[string]struct{}{},
+	}
+	if cond != nil {
+		it.cond = *cond
+	}
+	if it.cond.RequestRecordsAmount <= 0 || it.cond.RequestRecordsAmount > 1000 {
+		it.cond.RequestRecordsAmount = 1000
+	}
+	return it
+}
+
+// SearchOrderAll は注文検索の結果をすべてのページにわたって取得し、重複を除いた注文番号の一覧を返却します。
+func (a *RMSApi) SearchOrderAll(ctx context.Context, dateType SearchOrderDateType, startDatetime, endDatetime time.Time, cond *SearchOrderCondition) ([]string, error) {
+	oList := []string{}
+	it := a.SearchOrderIterator(ctx, dateType, startDatetime, endDatetime, cond)
+	for it.Next() {
+		oList = append(oList, it.OrderNumber())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return oList, nil
+}
+
+// Next は次の注文番号に進みます。次の注文番号がない場合や、エラーが発生した場合は false を返却します。
+// コンテキストがキャンセルされた場合も false を返却し、Err でその理由を取得できます。
+func (it *OrderNumberIterator) Next() bool {
+	for {
+		if it.err != nil {
+			return false
+		}
+		if err := it.ctx.Err(); err != nil {
+			it.err = err
+			return false
+		}
+		if len(it.buf) > 0 {
+			o := it.buf[0]
+			it.buf = it.buf[1:]
+			if _, ok := it.seen[o]; ok {
+				continue
+			}
+			it.seen[o] = struct{}{}
+			it.current = o
+			return true
+		}
+		if it.last {
+			return false
+		}
+		it.page++
+		it.cond.RequestPage = it.page
+		r, err := it.a.SearchOrderContext(it.ctx, it.dateType, it.startDatetime, it.endDatetime, &it.cond)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.buf = r.OrderNumberList
+		if len(r.OrderNumberList) == 0 || it.page >= r.TotalPages {
+			it.last = true
+		}
+	}
+}
+
+// OrderNumber は現在の注文番号を返却します。
+func (it *OrderNumberIterator) OrderNumber() string {
+	return it.current
+}
+
+// Err は取得中に発生したエラーを返却します。すべて取得できた場合は nil を返却します。
+func (it *OrderNumberIterator) Err() error {
+	return it.err
+}
+
+// SearchOrderRange は [startDatetime, endDatetime) の任意の期間の注文を検索し、重複を除いた注文番号の一覧を返却します。
+// 期間が63日を超える場合は63日ごとの期間に分割して、それぞれすべてのページを取得します。開始日時が2年より前の場合や、終了日時が開始日時以前の場合は ValidationError を返却します。
+func (a *RMSApi) SearchOrderRange(ctx context.Context, dateType SearchOrderDateType, startDatetime, endDatetime time.Time, cond *SearchOrderCondition) ([]string, error) {
+	windows, err := splitSearchOrderRange(startDatetime, endDatetime, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	oList := []string{}
+	seen := map[string]struct{}{}
+	for _, w := range windows {
+		it := a.SearchOrderIterator(ctx, dateType, w[0], w[1], cond)
+		for it.Next() {
+			o := it.OrderNumber()
+			if _, ok := seen[o]; ok {
+				continue
+			}
+			seen[o] = struct{}{}
+			oList = append(oList, o)
+		}
+		if err := it.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return oList, nil
+}
+
+// splitSearchOrderRange は [start, end) を注文検索で指定できる期間に分割します。RMSの終了日時は秒単位で終了日時を含むため、各期間の終了日時は次の期間の開始日時の1秒前になります。
+func splitSearchOrderRange(start, end, now time.Time) ([][2]time.Time, error) {
+	if !end.After(start) {
+		return nil, &ValidationError{Field: "endDatetime", Message: "終了日時は開始日時より後でなければいけません。"}
+	}
+	if start.Before(now.AddDate(-SEARCH_ORDER_MAX_YEARS, 0, 0)) {
+		return nil, &ValidationError{Field: "startDatetime", Message: "開始日時は過去2年以内でなければいけません。"}
+	}
+	windows := [][2]time.Time{}
+	for ws := start; ws.Before(end); {
+		we := ws.AddDate(0, 0, SEARCH_ORDER_MAX_DAYS)
+		if we.After(end) {
+			we = end
+		}
+		windows = append(windows, [2]time.Time{ws, we.Add(-time.Second)})
+		ws = we
+	}
+	return windows, nil
+}
+
+// OrderIterator は楽天ペイ受注APIの注文検索で得られた注文の注文情報を、1件ずつ取得するためのイテレータです。
+//
+//	it := a.SearchOrderDetail(ctx, DATE_TYPE_ORDER_DATE, start, end, nil, 4)
+//	for it.Next() {
+//		o := it.Order()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type OrderIterator struct {
+	a        *RMSApi
+	ctx      context.Context
+	dateType SearchOrderDateType
+	cond     *SearchOrderCondition
+	version  int
+
+	windows     [][2]time.Time
+	numbers     *OrderNumberIterator
+	seen        map[string]struct{}
+	last        bool
+	buf         []GetOrderOrderModel
+	current     GetOrderOrderModel
+	orderErrors []*OrderError
+	err         error
+}
+
+// SearchOrderDetail は [startDatetime, endDatetime) の期間の注文を検索し、その注文情報を順に取得する OrderIterator を生成します。v は注文情報の取得で使用するバージョン番号です。
+// 期間の分割とページングは SearchOrderRange と同様に行い、注文情報は100件ずつまとめて取得します。
+func (a *RMSApi) SearchOrderDetail(ctx context.Context, dateType SearchOrderDateType, startDatetime, endDatetime time.Time, cond *SearchOrderCondition, v int) *OrderIterator {
+	it := &OrderIterator{
+		a:        a,
+		ctx:      ctx,
+		dateType: dateType,
+		cond:     cond,
+		version:  v,
+		seen:     map[string]struct{}{},
+	}
+	it.windows, it.err = splitSearchOrderRange(startDatetime, endDatetime, time.Now())
+	return it
+}
+
+// Next は次の注文情報に進みます。次の注文情報がない場合や、エラーが発生した場合は false を返却します。
+// 注文ごとのエラーで取得できなかった注文は読み飛ばし、OrderErrors で取得することができます。
+func (it *OrderIterator) Next() bool {
+	for {
+		if it.err != nil {
+			return false
+		}
+		if err := it.ctx.Err(); err != nil {
+			it.err = err
+			return false
+		}
+		if len(it.buf) > 0 {
+			it.current = it.buf[0]
+			it.buf = it.buf[1:]
+			return true
+		}
+		if it.last {
+			return false
+		}
+		oList := it.nextOrderNumbers()
+		if it.err != nil {
+			return false
+		}
+		if len(oList) < GET_ORDER_MAX_ORDERS {
+			it.last = true
+		}
+		if len(oList) == 0 {
+			continue
+		}
+		r, err := it.a.GetOrderContext(it.ctx, oList, it.version)
+		apiErr := &APIError{}
+		switch {
+		case err == nil:
+			it.buf = r.OrderModelList
+			it.orderErrors = append(it.orderErrors, r.OrderErrors()...)
+		case errors.As(err, &apiErr) && len(apiErr.OrderErrors) > 0:
+			it.orderErrors = append(it.orderErrors, apiErr.OrderErrors...)
+		default:
+			it.err = err
+		}
+	}
+}
+
+// nextOrderNumbers は注文検索の結果から、まだ取得していない注文番号を最大100件取得します。
+func (it *OrderIterator) nextOrderNumbers() []string {
+	oList := []string{}
+	for len(oList) < GET_ORDER_MAX_ORDERS {
+		if it.numbers == nil {
+			if len(it.windows) == 0 {
+				return oList
+			}
+			w := it.windows[0]
+			it.windows = it.windows[1:]
+			it.numbers = it.a.SearchOrderIterator(it.ctx, it.dateType, w[0], w[1], it.cond)
+		}
+		if !it.numbers.Next() {
+			if err := it.numbers.Err(); err != nil {
+				it.err = err
+				return nil
+			}
+			it.numbers = nil
+			continue
+		}
+		o := it.numbers.OrderNumber()
+		if _, ok := it.seen[o]; ok {
+			continue
+		}
+		it.seen[o] = struct{}{}
+		oList = append(oList, o)
+	}
+	return oList
+}
+
+// Order は現在の注文情報を返却します。
+func (it *OrderIterator) Order() GetOrderOrderModel {
+	return it.current
+}
+
+// OrderErrors はこれまでに発生した注文ごとのエラーを返却します。
+func (it *OrderIterator) OrderErrors() []*OrderError {
+	return it.orderErrors
+}
+
+// Err は取得中に発生したエラーを返却します。すべて取得できた場合は nil を返却します。
+func (it *OrderIterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,166 @@
+package rms
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newSearchOrderServer はページごとに pages の注文番号を返却するテスト用のサーバを起動します。
+func newSearchOrderServer(pages [][]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := SearchOrderReuquest{}
+		json.NewDecoder(r.Body).Decode(&req)
+		res := SearchOrderResponse{}
+		res.CommonMessageModelResponseList = []CommonMessageModelResponse{{MessageType: "INFO", MessageCode: "ORDER_EXT_API_SEARCH_ORDER_INFO_101", Message: "注文検索に成功しました。"}}
+		res.OrderNumberList = []string{}
+		if p := req.RequestPage; p > 0 && p <= len(pages) {
+			res.OrderNumberList = pages[p-1]
+		}
+		res.TotalPages = len(pages)
+		res.RequestPage = req.RequestPage
+		json.NewEncoder(w).Encode(res)
+	}))
+}
+
+func TestSearchOrderAll_全ページ取得(t *testing.T) {
+	ts := newSearchOrderServer([][]string{{"1", "2"}, {"2", "3"}, {"4"}})
+	defer ts.Close()
+
+	a := NewRMSApi("hoge", "fuga", WithBaseURL(ts.URL))
+	oList, err := a.SearchOrderAll(context.Background(), DATE_TYPE_ORDER_DATE, time.Now().AddDate(0, 0, -7), time.Now(), nil)
+	if err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	if fmt.Sprint(oList) != "[1 2 3 4]" {
+		t.Errorf("expected: [1 2 3 4], actual: %v", oList)
+	}
+}
+
+func TestSearchOrderIterator_キャンセル(t *testing.T) {
+	ts := newSearchOrderServer([][]string{{"1", "2"}, {"3"}})
+	defer ts.Close()
+
+	a := NewRMSApi("hoge", "fuga", WithBaseURL(ts.URL))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	it := a.SearchOrderIterator(ctx, DATE_TYPE_ORDER_DATE, time.Now().AddDate(0, 0, -7), time.Now(), nil)
+	count := 0
+	for it.Next() {
+		count++
+		cancel()
+	}
+	if count != 1 {
+		t.Errorf("expected: 1, actual: %d", count)
+	}
+	if !errors.Is(it.Err(), context.Canceled) {
+		t.Errorf("expected: %v, actual: %v", context.Canceled, it.Err())
+	}
+}
+
+func TestSplitSearchOrderRange_期間分割(t *testing.T) {
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.Local)
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.Local)
+	windows, err := splitSearchOrderRange(start, now, now)
+	if err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	if len(windows) != 3 {
+		t.Errorf("expected: 3, actual: %d", len(windows))
+		t.FailNow()
+	}
+	if !windows[0][1].Equal(time.Date(2020, 3, 3, 23, 59, 59, 0, time.Local)) {
+		t.Errorf("expected: 2020-03-03 23:59:59, actual: %v", windows[0][1])
+	}
+	if !windows[1][0].Equal(time.Date(2020, 3, 4, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("expected: 2020-03-04 00:00:00, actual: %v", windows[1][0])
+	}
+	if !windows[2][1].Equal(now.Add(-time.Second)) {
+		t.Errorf("expected: %v, actual: %v", now.Add(-time.Second), windows[2][1])
+	}
+}
+
+func TestSearchOrderRange_2年より前(t *testing.T) {
+	a := NewRMSApi("hoge", "fuga")
+	_, err := a.SearchOrderRange(context.Background(), DATE_TYPE_ORDER_DATE, time.Now().AddDate(-3, 0, 0), time.Now(), nil)
+	ve := &ValidationError{}
+	if !errors.As(err, &ve) {
+		t.Errorf("expected: *ValidationError, actual: %v", err)
+		t.FailNow()
+	}
+	if ve.Field != "startDatetime" {
+		t.Errorf("expected: startDatetime, actual: %s", ve.Field)
+	}
+}
+
+func TestSearchOrderRange_期間をまたぐ検索(t *testing.T) {
+	ts := newSearchOrderServer([][]string{{"1", "2"}})
+	defer ts.Close()
+
+	a := NewRMSApi("hoge", "fuga", WithBaseURL(ts.URL))
+	oList, err := a.SearchOrderRange(context.Background(), DATE_TYPE_ORDER_DATE, time.Now().AddDate(0, 0, -100), time.Now(), nil)
+	if err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	if fmt.Sprint(oList) != "[1 2]" {
+		t.Errorf("expected: [1 2], actual: %v", oList)
+	}
+}
+
+func TestSearchOrderDetail_検索して取得(t *testing.T) {
+	oList := []string{}
+	for i := 0; i < 150; i++ {
+		oList = append(oList, fmt.Sprintf("%d", i))
+	}
+	search := newSearchOrderServer([][]string{oList[:80], oList[80:], {"NG-1"}})
+	defer search.Close()
+	getCalls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/es/2.0/order/searchOrder/" {
+			search.Config.Handler.ServeHTTP(w, r)
+			return
+		}
+		getCalls++
+		req := GetOrderRequest{}
+		json.NewDecoder(r.Body).Decode(&req)
+		res := GetOrderResponse{}
+		for _, o := range req.OrderNumberList {
+			if o == "NG-1" {
+				res.GetOrderMessageModelList = append(res.GetOrderMessageModelList, GetOrderMessageModel{CommonMessageModelResponse{"ERROR", "ORDER_EXT_API_GET_ORDER_ERROR_005", "注文番号が存在しません。"}, o})
+				continue
+			}
+			res.GetOrderMessageModelList = append(res.GetOrderMessageModelList, GetOrderMessageModel{CommonMessageModelResponse{"INFO", "ORDER_EXT_API_GET_ORDER_INFO_101", "受注情報取得に成功しました。"}, o})
+			res.OrderModelList = append(res.OrderModelList, GetOrderOrderModel{OrderNumber: o})
+		}
+		json.NewEncoder(w).Encode(res)
+	}))
+	defer ts.Close()
+
+	a := NewRMSApi("hoge", "fuga", WithBaseURL(ts.URL))
+	it := a.SearchOrderDetail(context.Background(), DATE_TYPE_ORDER_DATE, time.Now().AddDate(0, 0, -7), time.Now(), nil, 4)
+	count := 0
+	for it.Next() {
+		if it.Order().OrderNumber != oList[count] {
+			t.Errorf("expected: %s, actual: %s", oList[count], it.Order().OrderNumber)
+		}
+		count++
+	}
+	if err := it.Err(); err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	if count != 150 || getCalls != 2 {
+		t.Errorf("expected: 150 orders in 2 calls, actual: %d orders in %d calls", count, getCalls)
+	}
+	if len(it.OrderErrors()) != 1 || it.OrderErrors()[0].OrderNumber != "NG-1" {
+		t.Errorf("expected: NG-1, actual: %v", it.OrderErrors())
+	}
+}
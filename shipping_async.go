@@ -0,0 +1,282 @@
+package rms
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+const (
+	// UPDATE_ORDER_SHIPPING_ASYNC_MAX_ORDERS は発送情報の一括追加・更新(非同期)で一度に指定できる注文の数です。
+	UPDATE_ORDER_SHIPPING_ASYNC_MAX_ORDERS = 100
+
+	// UPDATE_ORDER_SHIPPING_ASYNC_DEFAULT_INTERVAL は WaitUpdateOrderShippingAsync で間隔を指定しない場合の結果取得の間隔です。
+	UPDATE_ORDER_SHIPPING_ASYNC_DEFAULT_INTERVAL = 10 * time.Second
+)
+
+// AsyncRequestStatus は非同期処理の処理状況を表します。
+type AsyncRequestStatus int
+
+const (
+	// ASYNC_REQUEST_STATUS_ACCEPTED は受付済みで、処理が開始されていないことを表します。
+	ASYNC_REQUEST_STATUS_ACCEPTED AsyncRequestStatus = 1
+
+	// ASYNC_REQUEST_STATUS_PROCESSING は処理中であることを表します。
+	ASYNC_REQUEST_STATUS_PROCESSING AsyncRequestStatus = 2
+
+	// ASYNC_REQUEST_STATUS_COMPLETED は処理が完了したことを表します。
+	ASYNC_REQUEST_STATUS_COMPLETED AsyncRequestStatus = 3
+)
+
+type (
+	/*** updateOrderShippingAsync ***/
+
+	// UpdateOrderShippingAsyncRequest は楽天ペイ受注APIの発送情報の一括追加・更新(非同期)のリクエストです。
+	UpdateOrderShippingAsyncRequest struct {
+		// OrderShippingModelList は注文ごとの発送情報の追加・更新の条件です。最大100件まで指定可能です。
+		OrderShippingModelList []UpdateOrderShippingCondition `json:"OrderShippingModelList"`
+	}
+
+	// UpdateOrderShippingAsyncResponse は楽天ペイ受注APIの発送情報の一括追加・更新(非同期)で得られるレスポンスです。
+	UpdateOrderShippingAsyncResponse struct {
+		// MessageModelList はメッセージモデルリストです。
+		MessageModelList []CommonMessageModelResponse `json:"MessageModelList"`
+
+		// RequestID は受け付けられたリクエストのIDです。結果の取得に使用します。
+		RequestID int `json:"requestId"`
+	}
+
+	/*** getResultUpdateOrderShippingAsync ***/
+
+	// GetResultUpdateOrderShippingAsyncRequest は楽天ペイ受注APIの発送情報の一括追加・更新(非同期)の結果取得のリクエストです。
+	GetResultUpdateOrderShippingAsyncRequest struct {
+		// RequestID はリクエストIDです。
+		RequestID int `json:"requestId"`
+	}
+
+	// UpdateOrderShippingAsyncResultModel は楽天ペイ受注APIの発送情報の一括追加・更新(非同期)の発送モデルごとの結果です。
+	UpdateOrderShippingAsyncResultModel struct {
+		// CommonMessageModelResponse は結果の情報が含まれます。
+		CommonMessageModelResponse
+
+		// OrderNumber は注文番号です。
+		OrderNumber string `json:"orderNumber"`
+
+		// DataNumber はデータ番号です。リクエストに含まれる発送モデルに、先頭から1始まりで振られた番号です。
+		DataNumber int `json:"dataNumber"`
+
+		// ShippingDetailID は発送明細IDです。新規の場合は採番された番号が、更新の場合は対象の番号が入力されます。
+		ShippingDetailID int `json:"shippingDetailId"`
+	}
+
+	// GetResultUpdateOrderShippingAsyncResponse は楽天ペイ受注APIの発送情報の一括追加・更新(非同期)の結果取得で得られるレスポンスです。
+	GetResultUpdateOrderShippingAsyncResponse struct {
+		// MessageModelList はメッセージモデルリストです。
+		MessageModelList []CommonMessageModelResponse `json:"MessageModelList"`
+
+		// RequestStatus は処理状況です。
+		RequestStatus AsyncRequestStatus `json:"requestStatus"`
+
+		// ResultModelList は発送モデルごとの結果です。処理が完了した場合のみ入力されます。
+		ResultModelList []UpdateOrderShippingAsyncResultModel `json:"asyncResultModelList"`
+	}
+
+	/*** job ***/
+
+	// UpdateOrderShippingAsyncRow はリクエストに含まれる発送モデル1件を表します。
+	UpdateOrderShippingAsyncRow struct {
+		// DataNumber はデータ番号です。
+		DataNumber int
+
+		// OrderNumber は注文番号です。
+		OrderNumber string
+
+		// BasketID は送付先IDです。
+		BasketID int
+
+		// ShippingModel は送信した発送モデルです。
+		ShippingModel UpdateOrderShippingShippingModelCondition
+	}
+
+	// UpdateOrderShippingAsyncJob は受け付けられた発送情報の一括追加・更新(非同期)のリクエストです。
+	UpdateOrderShippingAsyncJob struct {
+		// RequestID はリクエストIDです。
+		RequestID int
+
+		// Rows はデータ番号の順に並べた、送信した発送モデルです。
+		Rows []UpdateOrderShippingAsyncRow
+	}
+
+	// UpdateOrderShippingAsyncRowResult は発送モデルごとの処理結果です。
+	UpdateOrderShippingAsyncRowResult struct {
+		// UpdateOrderShippingAsyncRow は結果に対応する送信した発送モデルです。
+		UpdateOrderShippingAsyncRow
+
+		// CommonMessageModelResponse は結果の情報が含まれます。
+		CommonMessageModelResponse
+
+		// ShippingDetailID は発送明細IDです。
+		ShippingDetailID int
+	}
+)
+
+// Row はデータ番号に対応する送信した発送モデルを返却します。
+func (j *UpdateOrderShippingAsyncJob) Row(dataNumber int) (UpdateOrderShippingAsyncRow, bool) {
+	if dataNumber < 1 || dataNumber > len(j.Rows) {
+		return UpdateOrderShippingAsyncRow{}, false
+	}
+	return j.Rows[dataNumber-1], true
+}
+
+// Results は結果取得で得られた発送モデルごとの結果を、送信した発送モデルと対応付けて返却します。送信した発送モデルと対応付けられない結果は含まれません。
+func (j *UpdateOrderShippingAsyncJob) Results(r *GetResultUpdateOrderShippingAsyncResponse) []UpdateOrderShippingAsyncRowResult {
+	results := []UpdateOrderShippingAsyncRowResult{}
+	for _, m := range r.ResultModelList {
+		row, ok := j.Row(m.DataNumber)
+		if !ok {
+			continue
+		}
+		results = append(results, UpdateOrderShippingAsyncRowResult{row, m.CommonMessageModelResponse, m.ShippingDetailID})
+	}
+	return results
+}
+
+// OrderErrors は発送モデルごとの結果のうち、エラーとなったものを OrderError として返却します。
+func (j *UpdateOrderShippingAsyncJob) OrderErrors(r *GetResultUpdateOrderShippingAsyncResponse) []*OrderError {
+	errs := []*OrderError{}
+	for _, rr := range j.Results(r) {
+		if rr.MessageType == "ERROR" {
+			errs = append(errs, &OrderError{rr.CommonMessageModelResponse, rr.OrderNumber})
+		}
+	}
+	return errs
+}
+
+// newUpdateOrderShippingAsyncRows は conds に含まれる発送モデルに、先頭から順にデータ番号を振ります。
+func newUpdateOrderShippingAsyncRows(conds []UpdateOrderShippingCondition) []UpdateOrderShippingAsyncRow {
+	rows := []UpdateOrderShippingAsyncRow{}
+	for _, c := range conds {
+		for _, b := range c.BasketidModelList {
+			for _, sm := range b.ShippingModelList {
+				rows = append(rows, UpdateOrderShippingAsyncRow{len(rows) + 1, c.OrderNumber, b.BasketID, sm})
+			}
+		}
+	}
+	return rows
+}
+
+// UpdateOrderShippingAsync は楽天ペイ受注APIで複数の注文の発送情報の追加・更新を非同期で行うよう依頼します。conds は注文ごとの条件で、最大100件まで指定可能です。
+// 処理の結果は返却された UpdateOrderShippingAsyncJob を使用して、GetResultUpdateOrderShippingAsync または WaitUpdateOrderShippingAsync で取得することができます。
+// 100件を超える注文を依頼する場合は UpdateOrderShippingAsyncAll を使用してください。
+func (a *RMSApi) UpdateOrderShippingAsync(conds []UpdateOrderShippingCondition) (*UpdateOrderShippingAsyncJob, error) {
+	return a.UpdateOrderShippingAsyncContext(context.Background(), conds)
+}
+
+// UpdateOrderShippingAsyncContext は UpdateOrderShippingAsync にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) UpdateOrderShippingAsyncContext(ctx context.Context, conds []UpdateOrderShippingCondition) (*UpdateOrderShippingAsyncJob, error) {
+	if !a.initialized() {
+		return nil, ErrNotInitialized
+	}
+	if len(conds) == 0 || len(conds) > UPDATE_ORDER_SHIPPING_ASYNC_MAX_ORDERS {
+		return nil, &ValidationError{Field: "OrderShippingModelList", Message: "注文は1件以上100件以下でなければいけません。"}
+	}
+	status, byteArray, err := a.postJSON(ctx, UPDATE_ORDER_SHIPPING_ASYNC_URL, UpdateOrderShippingAsyncRequest{conds})
+	if err != nil {
+		return nil, err
+	}
+
+	result := UpdateOrderShippingAsyncResponse{}
+	err = json.Unmarshal(byteArray, &result)
+	if err != nil && isSuccessStatus(status) {
+		return nil, err
+	}
+	if !isSuccessStatus(status) || result.RequestID == 0 {
+		return nil, newAPIError(status, byteArray, result.MessageModelList)
+	}
+	return &UpdateOrderShippingAsyncJob{RequestID: result.RequestID, Rows: newUpdateOrderShippingAsyncRows(conds)}, nil
+}
+
+// UpdateOrderShippingAsyncAll は任意の数の注文の発送情報の追加・更新を、100件ずつのリクエストに分割して非同期で行うよう依頼します。
+// 依頼したリクエストごとの UpdateOrderShippingAsyncJob を conds の順に返却します。結果は WaitUpdateOrderShippingAsyncAll でまとめて取得することができます。
+// 途中の依頼でエラーになった場合は、それまでに受け付けられたリクエストとエラーを返却します。エラーになったリクエスト以降の注文は依頼されていません。
+func (a *RMSApi) UpdateOrderShippingAsyncAll(ctx context.Context, conds []UpdateOrderShippingCondition) ([]*UpdateOrderShippingAsyncJob, error) {
+	if !a.initialized() {
+		return nil, ErrNotInitialized
+	}
+	if len(conds) == 0 {
+		return nil, &ValidationError{Field: "OrderShippingModelList", Message: "注文は1件以上でなければいけません。"}
+	}
+	jobs := []*UpdateOrderShippingAsyncJob{}
+	for start := 0; start < len(conds); start += UPDATE_ORDER_SHIPPING_ASYNC_MAX_ORDERS {
+		end := start + UPDATE_ORDER_SHIPPING_ASYNC_MAX_ORDERS
+		if end > len(conds) {
+			end = len(conds)
+		}
+		job, err := a.UpdateOrderShippingAsyncContext(ctx, conds[start:end])
+		if err != nil {
+			return jobs, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// GetResultUpdateOrderShippingAsync は楽天ペイ受注APIで発送情報の一括追加・更新(非同期)の処理状況と結果を取得します。requestID は UpdateOrderShippingAsync で得られたリクエストIDです。
+func (a *RMSApi) GetResultUpdateOrderShippingAsync(requestID int) (*GetResultUpdateOrderShippingAsyncResponse, error) {
+	return a.GetResultUpdateOrderShippingAsyncContext(context.Background(), requestID)
+}
+
+// GetResultUpdateOrderShippingAsyncContext は GetResultUpdateOrderShippingAsync にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) GetResultUpdateOrderShippingAsyncContext(ctx context.Context, requestID int) (*GetResultUpdateOrderShippingAsyncResponse, error) {
+	if !a.initialized() {
+		return nil, ErrNotInitialized
+	}
+	status, byteArray, err := a.postJSON(ctx, GET_RESULT_UPDATE_ORDER_SHIPPING_ASYNC_URL, GetResultUpdateOrderShippingAsyncRequest{requestID})
+	if err != nil {
+		return nil, err
+	}
+
+	result := GetResultUpdateOrderShippingAsyncResponse{}
+	err = json.Unmarshal(byteArray, &result)
+	if err != nil && isSuccessStatus(status) {
+		return nil, err
+	}
+	if !isSuccessStatus(status) || result.RequestStatus == 0 {
+		return nil, newAPIError(status, byteArray, result.MessageModelList)
+	}
+	return &result, nil
+}
+
+// WaitUpdateOrderShippingAsync は発送情報の一括追加・更新(非同期)の処理が完了するまで interval ごとに結果を取得し、送信した発送モデルと対応付けた結果を返却します。
+// interval が0以下の場合は UPDATE_ORDER_SHIPPING_ASYNC_DEFAULT_INTERVAL ごとに取得します。ctx がキャンセルされた場合は待機を中断してエラーを返却します。
+func (a *RMSApi) WaitUpdateOrderShippingAsync(ctx context.Context, job *UpdateOrderShippingAsyncJob, interval time.Duration) ([]UpdateOrderShippingAsyncRowResult, error) {
+	if interval <= 0 {
+		interval = UPDATE_ORDER_SHIPPING_ASYNC_DEFAULT_INTERVAL
+	}
+	for {
+		r, err := a.GetResultUpdateOrderShippingAsyncContext(ctx, job.RequestID)
+		if err != nil {
+			return nil, err
+		}
+		if r.RequestStatus == ASYNC_REQUEST_STATUS_COMPLETED {
+			return job.Results(r), nil
+		}
+		if err := sleep(ctx, interval); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// WaitUpdateOrderShippingAsyncAll は UpdateOrderShippingAsyncAll で依頼したすべてのリクエストの処理が完了するまで待機し、送信した発送モデルと対応付けた結果を jobs の順にまとめて返却します。
+// interval は WaitUpdateOrderShippingAsync と同じです。
+func (a *RMSApi) WaitUpdateOrderShippingAsyncAll(ctx context.Context, jobs []*UpdateOrderShippingAsyncJob, interval time.Duration) ([]UpdateOrderShippingAsyncRowResult, error) {
+	results := []UpdateOrderShippingAsyncRowResult{}
+	for _, job := range jobs {
+		r, err := a.WaitUpdateOrderShippingAsync(ctx, job, interval)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, r...)
+	}
+	return results, nil
+}
@@ -0,0 +1,136 @@
+package rms_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	rms "github.com/hayabusa-systems/rms-go-sdk"
+)
+
+func TestUpdateOrderShippingAsyncJob_データ番号の対応付け(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+
+	sn := func(s string) *string { return &s }
+	conds := []rms.UpdateOrderShippingCondition{
+		{OrderNumber: "A", BasketidModelList: []rms.UpdateOrderShippingBasketidModelCondition{
+			{BasketID: 1, ShippingModelList: []rms.UpdateOrderShippingShippingModelCondition{{ShippingNumber: sn("a1")}, {ShippingNumber: sn("a2")}}},
+			{BasketID: 2, ShippingModelList: []rms.UpdateOrderShippingShippingModelCondition{{ShippingNumber: sn("a3")}}},
+		}},
+		{OrderNumber: "B", BasketidModelList: []rms.UpdateOrderShippingBasketidModelCondition{
+			{BasketID: 3, ShippingModelList: []rms.UpdateOrderShippingShippingModelCondition{{ShippingNumber: sn("b1")}}},
+		}},
+	}
+	job, err := s.Client().UpdateOrderShippingAsync(conds)
+	if err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	r := &rms.GetResultUpdateOrderShippingAsyncResponse{
+		RequestStatus: rms.ASYNC_REQUEST_STATUS_COMPLETED,
+		ResultModelList: []rms.UpdateOrderShippingAsyncResultModel{
+			{CommonMessageModelResponse: rms.CommonMessageModelResponse{MessageType: "ERROR"}, DataNumber: 4},
+			{CommonMessageModelResponse: rms.CommonMessageModelResponse{MessageType: "INFO"}, DataNumber: 3},
+			{CommonMessageModelResponse: rms.CommonMessageModelResponse{MessageType: "INFO"}, DataNumber: 5},
+		},
+	}
+	results := job.Results(r)
+	if len(results) != 2 {
+		t.Errorf("expected: 2, actual: %d", len(results))
+		t.FailNow()
+	}
+	if results[0].OrderNumber != "B" || *results[0].ShippingModel.ShippingNumber != "b1" {
+		t.Errorf("expected: B b1, actual: %v", results[0])
+	}
+	if results[1].OrderNumber != "A" || results[1].BasketID != 2 || *results[1].ShippingModel.ShippingNumber != "a3" {
+		t.Errorf("expected: A 2 a3, actual: %v", results[1])
+	}
+	if oe := job.OrderErrors(r); len(oe) != 1 || oe[0].OrderNumber != "B" {
+		t.Errorf("expected: B, actual: %v", oe)
+	}
+}
+
+func TestUpdateOrderShippingAsync_一括更新(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+
+	a := s.Client()
+	dc := "1001"
+	sn1, sn2, sn3 := "1111-1111-1111", "2222-2222-2222", "3333-3333-3333"
+	conds := []rms.UpdateOrderShippingCondition{
+		{OrderNumber: testOrderNumber, BasketidModelList: []rms.UpdateOrderShippingBasketidModelCondition{
+			{BasketID: testBasketID, ShippingModelList: []rms.UpdateOrderShippingShippingModelCondition{{DeliveryCompany: &dc, ShippingNumber: &sn1}}},
+		}},
+		{OrderNumber: "000000-20200101-9999999999", BasketidModelList: []rms.UpdateOrderShippingBasketidModelCondition{
+			{BasketID: 99, ShippingModelList: []rms.UpdateOrderShippingShippingModelCondition{{DeliveryCompany: &dc, ShippingNumber: &sn2}}},
+		}},
+		{OrderNumber: "000000-20200101-0000000002", BasketidModelList: []rms.UpdateOrderShippingBasketidModelCondition{
+			{BasketID: testBasketID + 1, ShippingModelList: []rms.UpdateOrderShippingShippingModelCondition{{DeliveryCompany: &dc, ShippingNumber: &sn3}}},
+		}},
+	}
+	job, err := a.UpdateOrderShippingAsync(conds)
+	if err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	results, err := a.WaitUpdateOrderShippingAsync(context.Background(), job, time.Millisecond)
+	if err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	if len(results) != 3 || results[1].MessageType != "ERROR" || *results[2].ShippingModel.ShippingNumber != sn3 || results[2].OrderNumber != "000000-20200101-0000000002" {
+		t.Errorf("expected: 3 results with an error on row 2, actual: %v", results)
+	}
+	if o, _ := s.Order("000000-20200101-0000000002"); len(o.PackageModelList[0].ShippingModelList) != 2 {
+		t.Errorf("expected: 2, actual: %d", len(o.PackageModelList[0].ShippingModelList))
+	}
+
+	_, err = a.UpdateOrderShippingAsync(nil)
+	ve := &rms.ValidationError{}
+	if !errors.As(err, &ve) {
+		t.Errorf("expected: *rms.ValidationError, actual: %v", err)
+	}
+}
+
+func TestUpdateOrderShippingAsyncAll_100件超(t *testing.T) {
+	s := newTestServer()
+	defer s.Close()
+
+	a := s.Client()
+	dc := "1001"
+	sn := "1111-1111-1111"
+	conds := []rms.UpdateOrderShippingCondition{}
+	for i := 0; i < rms.UPDATE_ORDER_SHIPPING_ASYNC_MAX_ORDERS*2+1; i++ {
+		n, basket := fmt.Sprintf("000000-20200101-9%09d", i), 99
+		if i == rms.UPDATE_ORDER_SHIPPING_ASYNC_MAX_ORDERS*2 {
+			n, basket = "000000-20200101-0000000003", testBasketID+2
+		}
+		conds = append(conds, rms.UpdateOrderShippingCondition{OrderNumber: n, BasketidModelList: []rms.UpdateOrderShippingBasketidModelCondition{
+			{BasketID: basket, ShippingModelList: []rms.UpdateOrderShippingShippingModelCondition{{DeliveryCompany: &dc, ShippingNumber: &sn}}},
+		}})
+	}
+	jobs, err := a.UpdateOrderShippingAsyncAll(context.Background(), conds)
+	if err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	if len(jobs) != 3 {
+		t.Errorf("expected: 3, actual: %d", len(jobs))
+	}
+	results, err := a.WaitUpdateOrderShippingAsyncAll(context.Background(), jobs, time.Millisecond)
+	if err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	if len(results) != len(conds) {
+		t.Errorf("expected: %d, actual: %d", len(conds), len(results))
+		t.FailNow()
+	}
+	last := results[len(results)-1]
+	if last.OrderNumber != "000000-20200101-0000000003" || last.MessageType != "INFO" || results[0].MessageType != "ERROR" {
+		t.Errorf("expected: INFO for the last order only, actual: %v %v", results[0], last)
+	}
+}
@@ -1,17 +1,25 @@
 package rms
 
 import (
+	"context"
 	"encoding/xml"
-	"errors"
 	"fmt"
-	"io/ioutil"
-	"net/http"
+	"net/url"
 	"time"
 )
 
 const (
 	// RMS WEB SERVICEの店舗APIの営業日カレンダー設定・長期休暇の告知情報取得用のエンドポイントです。
 	SHOP_CALENDAR_URL = "https://api.rms.rakuten.co.jp/es/1.0/shop/shopCalendar"
+
+	// RMS WEB SERVICEの店舗APIの営業日カレンダー設定・長期休暇の告知情報更新用のエンドポイントです。
+	SHOP_CALENDAR_UPDATE_URL = "https://api.rms.rakuten.co.jp/es/1.0/shop/shopCalendar/update"
+
+	// SHOP_HOLIDAY_TIMESTAMP_FORMAT は長期休暇の告知の表示期間の形式です。
+	SHOP_HOLIDAY_TIMESTAMP_FORMAT = "2006-01-02T15:04:05+09:00"
+
+	// CALENDAR_EVENT_DATE_FORMAT は営業日カレンダーの日付の形式です。
+	CALENDAR_EVENT_DATE_FORMAT = "20060102"
 )
 
 type (
@@ -81,10 +89,20 @@ type (
 		EventDates CaleendarEventEventDate `xml:"eventDates,omitempty"`
 	}
 
+	// ShopCalendarUpdateRequest は店舗APIの営業日カレンダー設定・長期休暇の告知情報更新のリクエストです。
+	ShopCalendarUpdateRequest struct {
+		XMLName xml.Name `xml:"request"`
+
+		// Calendar は更新後の営業日カレンダーです。
+		Calendar ShopCalendar `xml:"shopCalendar"`
+	}
+
+	// CaleendarEventWeekday は定期的な休日(曜日)を格納する構造体です。曜日は SUN、MON、TUE、WED、THU、FRI、SAT のいずれかです。
 	CaleendarEventWeekday struct {
 		Weekday []string `xml:"weekday"`
 	}
 
+	// CaleendarEventEventDate は日付を格納する構造体です。日付はYYYYMMDDの形式です。
 	CaleendarEventEventDate struct {
 		EventDate []string `xml:"eventDate"`
 	}
@@ -95,22 +113,22 @@ type (
 		Title string `xml:"title,omitempty"`
 		// StimestampYmd はWeb告知用表示期間の開始日です。YYYY-MM-DDThh:mm:ss+09:00の形式です。
 		StimestampYmd string `xml:"stimestampYmd,omitempty"`
-		// Stimestamp はWeb告知用表示期間の開始日です。StimestampYmdをxmlから構造体に戻すときデータを格納します。
-		Stimestamp *time.Time
+		// Stimestamp はWeb告知用表示期間の開始日です。GetShopCalendar で取得した場合は StimestampYmd から変換した日時が格納されます。形式が不正で変換できない場合は nil です。
+		Stimestamp *time.Time `xml:"-"`
 		// EtimestampYmd はWeb告知用表示期間の終了日です。YYYY-MM-DDThh:mm:ss+09:00の形式です。
 		EtimestampYmd string `xml:"etimestampYmd,omitempty"`
-		// Etimestamp はWeb告知用表示期間の開始日です。EtimestampYmdをxmlから構造体に戻すときデータを格納します。
-		Etimestamp *time.Time
+		// Etimestamp はWeb告知用表示期間の終了日です。GetShopCalendar で取得した場合は EtimestampYmd から変換した日時が格納されます。形式が不正で変換できない場合は nil です。
+		Etimestamp *time.Time `xml:"-"`
 		// MailMessageはメール告知用メッセージです。3072バイトが最大です。
 		MailMessage string `xml:"mailMessage,omitempty"`
 		// StimestampMailYmd はメール告知用表示期間の開始日です。YYYY-MM-DDThh:mm:ss+09:00の形式です。
 		StimestampMailYmd string `xml:"stimestampMailYmd,omitempty"`
-		// StimestampMail はWeb告知用表示期間の開始日です。StimestampMailYmdをxmlから構造体に戻すときデータを格納します。
-		StimestampMail *time.Time
+		// StimestampMail はメール告知用表示期間の開始日です。GetShopCalendar で取得した場合は StimestampMailYmd から変換した日時が格納されます。形式が不正で変換できない場合は nil です。
+		StimestampMail *time.Time `xml:"-"`
 		// EtimestampMailYmd はメール告知用表示期間の終了日です。YYYY-MM-DDThh:mm:ss+09:00の形式です。
 		EtimestampMailYmd string `xml:"etimestampMailYmd,omitempty"`
-		// EtimestampMail はWeb告知用表示期間の開始日です。EtimestampMailYmdをxmlから構造体に戻すときデータを格納します。
-		EtimestampMail *time.Time
+		// EtimestampMail はメール告知用表示期間の終了日です。GetShopCalendar で取得した場合は EtimestampMailYmd から変換した日時が格納されます。形式が不正で変換できない場合は nil です。
+		EtimestampMail *time.Time `xml:"-"`
 		// MessageはWeb告知用メッセージです。3072バイトが最大です。
 		Message string `xml:"message,omitempty"`
 	}
@@ -118,15 +136,16 @@ type (
 
 // GetShopCalendar はRMSから営業日カレンダー・長期休暇の告知を取得します。fromDate は開始年月日で、YYYY-MM-DDの形式で渡します。指定されない場合は、現在年月日以降の情報を取得します。period は取得する期間です。1~180まで指定することができます。それ以外の場合は90日分のデータを取得します。
 func (a *RMSApi) GetShopCalendar(fromDate string, period int) (*ShopBizApiResponse, error) {
-	if a.authorization == "" {
-		return nil, errors.New("Uninitialized")
-	}
+	return a.GetShopCalendarContext(context.Background(), fromDate, period)
+}
 
-	req, _ := http.NewRequest("GET", SHOP_CALENDAR_URL, nil)
-	req.Header.Set("Authorization", "ESA "+a.authorization)
-	req.Header.Set("Content-Type", "application/sml; charset=utf-8")
+// GetShopCalendarContext は GetShopCalendar にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) GetShopCalendarContext(ctx context.Context, fromDate string, period int) (*ShopBizApiResponse, error) {
+	if !a.initialized() {
+		return nil, ErrNotInitialized
+	}
 
-	params := req.URL.Query()
+	params := url.Values{}
 	_, err := time.Parse("2006-01-02", fromDate)
 	if fromDate != "" && err == nil {
 		params.Add("fromDate", fromDate)
@@ -134,20 +153,136 @@ func (a *RMSApi) GetShopCalendar(fromDate string, period int) (*ShopBizApiRespon
 	if period > 0 && period <= 180 {
 		params.Add("period", fmt.Sprintf("%d", period))
 	}
-	req.URL.RawQuery = params.Encode()
 
-	client := new(http.Client)
-	resp, err := client.Do(req)
-	defer resp.Body.Close()
+	status, byteArray, err := a.send(ctx, "GET", SHOP_CALENDAR_URL, "application/xml; charset=utf-8", params, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	byteArray, _ := ioutil.ReadAll(resp.Body)
 	result := ShopBizApiResponse{}
 	err = xml.Unmarshal(byteArray, &result)
-	if err != nil {
+	if err != nil && isSuccessStatus(status) {
 		return nil, err
 	}
+	if !isSuccessStatus(status) {
+		return nil, newAPIError(status, byteArray, result.ResultMessageList.messageModelList())
+	}
+	if result.Result != nil {
+		// 告知の表示期間の形式が不正でも営業日カレンダーは利用できるため、変換できない日時を nil のままにして返却します。
+		result.Result.Calendar.ShopHoliday.parseTimestamps()
+	}
 	return &result, nil
 }
+
+// UpdateShopCalendar はRMSの営業日カレンダー・長期休暇の告知を c の内容で更新します。c に含まれない日付の設定は削除されます。
+// 長期休暇の告知の表示期間は、Stimestamp 等に日時が設定されている場合はそちらを優先して StimestampYmd 等の形式に変換して送信します。
+func (a *RMSApi) UpdateShopCalendar(c *ShopCalendar) error {
+	return a.UpdateShopCalendarContext(context.Background(), c)
+}
+
+// UpdateShopCalendarContext は UpdateShopCalendar にコンテキストを指定できるようにしたものです。ctx がキャンセルされた場合、通信を中断してエラーを返却します。
+func (a *RMSApi) UpdateShopCalendarContext(ctx context.Context, c *ShopCalendar) error {
+	if !a.initialized() {
+		return ErrNotInitialized
+	}
+	req := ShopCalendarUpdateRequest{Calendar: *c}
+	req.Calendar.ShopHoliday = c.ShopHoliday.formatted()
+	if err := req.Calendar.validate(); err != nil {
+		return err
+	}
+	result := ShopBizApiResponse{}
+	return a.sendResultXML(ctx, "POST", SHOP_CALENDAR_UPDATE_URL, nil, &req, &result, &result.ResultCode, &result.ResultMessageList)
+}
+
+// formatted は表示期間の日時を StimestampYmd 等の形式に変換した長期休暇の告知を返却します。
+func (h ShopHoliday) formatted() ShopHoliday {
+	format := func(t *time.Time, s string) string {
+		if t == nil {
+			return s
+		}
+		return t.In(jst).Format(SHOP_HOLIDAY_TIMESTAMP_FORMAT)
+	}
+	h.StimestampYmd = format(h.Stimestamp, h.StimestampYmd)
+	h.EtimestampYmd = format(h.Etimestamp, h.EtimestampYmd)
+	h.StimestampMailYmd = format(h.StimestampMail, h.StimestampMailYmd)
+	h.EtimestampMailYmd = format(h.EtimestampMail, h.EtimestampMailYmd)
+	return h
+}
+
+// validate は送信前に営業日カレンダーの曜日、日付、告知の表示期間の形式を検証します。
+func (c *ShopCalendar) validate() error {
+	for _, e := range []CalendarEvent{c.BusinessHoliday, c.ShippingHoliday, c.ShippingOnly} {
+		for _, w := range e.RegularSchedule.Weekday {
+			if _, ok := calendarWeekdays[w]; !ok {
+				return &ValidationError{Field: "weekday", Message: fmt.Sprintf("曜日 %s が不正です。", w)}
+			}
+		}
+		for _, d := range e.EventDates.EventDate {
+			if _, err := time.Parse(CALENDAR_EVENT_DATE_FORMAT, d); err != nil {
+				return &ValidationError{Field: "eventDate", Message: fmt.Sprintf("日付 %s はYYYYMMDDの形式で指定してください。", d)}
+			}
+		}
+	}
+	h := c.ShopHoliday
+	periods := []struct{ field, start, end string }{
+		{"stimestampYmd", h.StimestampYmd, h.EtimestampYmd},
+		{"stimestampMailYmd", h.StimestampMailYmd, h.EtimestampMailYmd},
+	}
+	for _, p := range periods {
+		if p.start == "" && p.end == "" {
+			continue
+		}
+		s, serr := time.Parse(SHOP_HOLIDAY_TIMESTAMP_FORMAT, p.start)
+		e, eerr := time.Parse(SHOP_HOLIDAY_TIMESTAMP_FORMAT, p.end)
+		if serr != nil || eerr != nil {
+			return &ValidationError{Field: p.field, Message: "告知の表示期間は開始日時と終了日時をYYYY-MM-DDThh:mm:ss+09:00の形式で指定してください。"}
+		}
+		if e.Before(s) {
+			return &ValidationError{Field: p.field, Message: "告知の表示期間の終了日時は開始日時以降でなければいけません。"}
+		}
+	}
+	return nil
+}
+
+// sendResultXML は店舗API等の resultCode と resultMessageList を含むXMLのAPIにリクエストを送信し、レスポンスを result に格納します。
+// code と messages は result に含まれる結果コードとメッセージ一覧で、結果コードがN000以外の場合は APIError を返却します。
+func (a *RMSApi) sendResultXML(ctx context.Context, method, u string, params url.Values, req interface{}, result interface{}, code *string, messages *ResultMessageList) error {
+	var body []byte
+	if req != nil {
+		b, err := xml.Marshal(req)
+		if err != nil {
+			return err
+		}
+		body = append([]byte(xml.Header), b...)
+	}
+	status, byteArray, err := a.send(ctx, method, u, "application/xml; charset=utf-8", params, body)
+	if err != nil {
+		return err
+	}
+
+	err = xml.Unmarshal(byteArray, result)
+	if err != nil && isSuccessStatus(status) {
+		return err
+	}
+	if !isSuccessStatus(status) || *code != "N000" {
+		list := messages.messageModelList()
+		if len(list) == 0 && *code != "" {
+			list = append(list, CommonMessageModelResponse{MessageType: "ERROR", MessageCode: *code})
+		}
+		return newAPIError(status, byteArray, list)
+	}
+	return nil
+}
+
+// messageModelList はXMLのメッセージ一覧を楽天ペイ受注APIと同じ形式に変換します。結果コードがN000以外のものはERRORとして扱います。
+func (l ResultMessageList) messageModelList() []CommonMessageModelResponse {
+	list := []CommonMessageModelResponse{}
+	for _, m := range l.List {
+		t := "ERROR"
+		if m.Code == "N000" {
+			t = "INFO"
+		}
+		list = append(list, CommonMessageModelResponse{MessageType: t, MessageCode: m.Code, Message: m.Message})
+	}
+	return list
+}
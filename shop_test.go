@@ -1,13 +1,28 @@
-package rms
+package rms_test
 
 import (
-	"os"
+	"errors"
 	"testing"
+	"time"
+
+	rms "github.com/hayabusa-systems/rms-go-sdk"
+	"github.com/hayabusa-systems/rms-go-sdk/rmstest"
 )
 
+// newCalendarTestServer は日曜日と2020年5月5日、8月1日を休業日とした営業日カレンダーを設定した擬似的なRMSのサーバを起動します。
+func newCalendarTestServer() *rmstest.Server {
+	s := rmstest.NewServer("hoge", "fuga")
+	c := rms.ShopCalendar{}
+	c.BusinessHoliday.RegularSchedule.Weekday = []string{"SUN"}
+	c.BusinessHoliday.EventDates.EventDate = []string{"20200505", "20200801"}
+	s.SetShopCalendar(c)
+	return s
+}
+
 func TestGetShopCalendar_条件指定なし(t *testing.T) {
-	a := RMSApi{}
-	a.Initialize(os.Getenv("SERVICE_SECRET"), os.Getenv("LICENSE_KEY"))
+	s := newCalendarTestServer()
+	defer s.Close()
+	a := s.Client()
 	r, err := a.GetShopCalendar("", -1)
 	if err != nil {
 		t.Errorf("このテストは正常にデータが取得できることを期待するテストですが、エラーが発生しました。%v", err)
@@ -21,8 +36,9 @@ func TestGetShopCalendar_条件指定なし(t *testing.T) {
 }
 
 func TestGetShopCalendar_日付指定(t *testing.T) {
-	a := RMSApi{}
-	a.Initialize(os.Getenv("SERVICE_SECRET"), os.Getenv("LICENSE_KEY"))
+	s := newCalendarTestServer()
+	defer s.Close()
+	a := s.Client()
 	r, err := a.GetShopCalendar("2020-05-15", -1)
 	if err != nil {
 		t.Errorf("このテストは正常にデータが取得できることを期待するテストですが、エラーが発生しました。%v", err)
@@ -32,5 +48,67 @@ func TestGetShopCalendar_日付指定(t *testing.T) {
 		t.Error("営業日カレンダーの取得に失敗しました。")
 		t.FailNow()
 	}
+	if d := r.Result.Calendar.BusinessHoliday.EventDates.EventDate; len(d) != 1 || d[0] != "20200801" {
+		t.Errorf("expected: [20200801], actual: %v", d)
+	}
+}
+
+func TestGetShopCalendar_告知期間の形式不正(t *testing.T) {
+	s := newCalendarTestServer()
+	defer s.Close()
+	c := rms.ShopCalendar{}
+	c.BusinessHoliday.RegularSchedule.Weekday = []string{"SUN"}
+	c.ShopHoliday.StimestampYmd = "2020/04/20 00:00:00"
+	s.SetShopCalendar(c)
+
+	a := s.Client()
+	r, err := a.GetShopCalendar("", -1)
+	if err != nil {
+		t.Errorf("このテストは正常にデータが取得できることを期待するテストですが、エラーが発生しました。%v", err)
+		t.FailNow()
+	}
+	if r.Result == nil || len(r.Result.Calendar.BusinessHoliday.RegularSchedule.Weekday) != 1 {
+		t.Error("営業日カレンダーの取得に失敗しました。")
+		t.FailNow()
+	}
+	if h := r.Result.Calendar.ShopHoliday; h.StimestampYmd != "2020/04/20 00:00:00" || h.Stimestamp != nil {
+		t.Errorf("expected: 2020/04/20 00:00:00 and nil, actual: %s %v", h.StimestampYmd, h.Stimestamp)
+	}
+}
+
+func TestUpdateShopCalendar_休業日と告知期間の更新(t *testing.T) {
+	s := rmstest.NewServer("hoge", "fuga")
+	defer s.Close()
+
+	start := time.Date(2020, 4, 20, 0, 0, 0, 0, time.FixedZone("Asia/Tokyo", 9*60*60))
+	end := start.AddDate(0, 0, 20)
+	c := rms.ShopCalendar{}
+	c.BusinessHoliday.RegularSchedule.Weekday = []string{"SUN", "SAT"}
+	c.BusinessHoliday.EventDates.EventDate = []string{"20200429", "20200503", "20200504", "20200505", "20200506"}
+	c.ShopHoliday.Title = "ゴールデンウィーク休業のお知らせ"
+	c.ShopHoliday.Stimestamp = &start
+	c.ShopHoliday.Etimestamp = &end
+
+	a := s.Client()
+	if err := a.UpdateShopCalendar(&c); err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	r, err := a.GetShopCalendar("2020-04-20", 30)
+	if err != nil {
+		t.Errorf("Happend undefined error: %v", err)
+		t.FailNow()
+	}
+	got := r.Result.Calendar
+	if len(got.BusinessHoliday.EventDates.EventDate) != 5 || len(got.BusinessHoliday.RegularSchedule.Weekday) != 2 {
+		t.Errorf("expected: 5 dates and 2 weekdays, actual: %v", got.BusinessHoliday)
+	}
+	if got.ShopHoliday.StimestampYmd != "2020-04-20T00:00:00+09:00" || got.ShopHoliday.EtimestampYmd != "2020-05-10T00:00:00+09:00" {
+		t.Errorf("expected: 2020-04-20T00:00:00+09:00 - 2020-05-10T00:00:00+09:00, actual: %s - %s", got.ShopHoliday.StimestampYmd, got.ShopHoliday.EtimestampYmd)
+	}
 
+	c.BusinessHoliday.RegularSchedule.Weekday = []string{"SUNDAY"}
+	if err := a.UpdateShopCalendar(&c); !errors.As(err, new(*rms.ValidationError)) {
+		t.Errorf("expected: ValidationError, actual: %v", err)
+	}
 }